@@ -0,0 +1,54 @@
+package altdata
+
+import "errors"
+import "math/big"
+
+// Writes x as a sign byte (1 if negative, otherwise 0) followed by the big endian
+// magnitude with a length prefix, see SetLengthPrefix.
+// Returns an error and does not move the position if it does not fit.
+func (b *Buffer) WriteBigInt(x *big.Int) error {
+	var sign uint8
+	if x.Sign() < 0 {
+		sign = 1
+	}
+	magnitude := x.Bytes()
+	start := b.writePosition()
+	err := b.WriteUint8(sign)
+	if err == nil {
+		err = b.writeLength(len(magnitude))
+	}
+	if err == nil {
+		var p []byte
+		if p, err = b.reserve(len(magnitude)); err == nil {
+			copy(p, magnitude)
+		}
+	}
+	if err != nil {
+		b.setWritePosition(start)
+	}
+	return err
+}
+
+// Reads a big.Int written by WriteBigInt.
+// Returns an error and does not move the position if it is truncated.
+func (b *Buffer) ReadBigInt() (*big.Int, error) {
+	start := b.position
+	sign, err := b.ReadUint8()
+	if err != nil {
+		return nil, err
+	}
+	if sign > 1 {
+		b.position = start
+		return nil, errors.New("Invalid big.Int sign")
+	}
+	n, err := b.readLength()
+	if err != nil {
+		b.position = start
+		return nil, err
+	}
+	x := new(big.Int).SetBytes(b.Next(n))
+	if sign == 1 {
+		x.Neg(x)
+	}
+	return x, nil
+}
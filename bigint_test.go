@@ -0,0 +1,58 @@
+package altdata
+
+import "errors"
+import "math/big"
+import "testing"
+
+func TestBigIntRoundTrip(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	values := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(-1), big.NewInt(-300), huge, new(big.Int).Neg(huge)}
+	for _, prefix := range []LengthPrefix{PrefixUint16, PrefixUint8, PrefixVarint} {
+		b := NewBuffer(64)
+		b.SetLengthPrefix(prefix)
+		for _, x := range values {
+			if err := b.WriteBigInt(x); err != nil {
+				t.Fatal(x, err)
+			}
+		}
+		b.Flip()
+		for _, x := range values {
+			if r, err := b.ReadBigInt(); err != nil || r.Cmp(x) != 0 {
+				t.Fatal(prefix, x, r, err)
+			}
+		}
+		if b.Remaining() != 0 {
+			t.Fatal(b.Remaining())
+		}
+	}
+}
+
+func TestBigIntInvalidSign(t *testing.T) {
+	b := Wrap([]byte{2, 0, 1, 5})
+	if x, err := b.ReadBigInt(); err == nil || x != nil || b.Position() != 0 {
+		t.Fatal(x, err, b.Position())
+	}
+}
+
+func TestBigIntTruncated(t *testing.T) {
+	b := NewBuffer(16)
+	b.WriteBigInt(big.NewInt(-0x123456))
+	b.Flip()
+	b.Truncate(b.Limit() - 1)
+	if x, err := b.ReadBigInt(); !errors.Is(err, ErrBufferEmpty) || x != nil || b.Position() != 0 {
+		t.Fatal(x, err, b.Position())
+	}
+	// Truncated within the length prefix.
+	b = Wrap([]byte{1, 0})
+	if _, err := b.ReadBigInt(); err == nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+}
+
+func TestBigIntFull(t *testing.T) {
+	b := NewBuffer(4)
+	b.WriteUint8(7)
+	if err := b.WriteBigInt(big.NewInt(0x123456)); err == nil || b.Position() != 1 {
+		t.Fatal(err, b.Position())
+	}
+}
@@ -0,0 +1,129 @@
+package altdata
+
+import "errors"
+import "fmt"
+
+// Order of bits within each byte for BitReader and BitWriter.
+type BitOrder int
+
+const (
+	MSBFirst BitOrder = iota // Most significant bit first. Default.
+	LSBFirst                 // Least significant bit first.
+)
+
+// BitReader reads fields of up to 64 bits not aligned to bytes from a Buffer.
+type BitReader struct {
+	buffer *Buffer
+	order  BitOrder
+	cur    byte
+	nbits  uint // Unread bits in cur.
+}
+
+// Creates a bit reader reading from the position of buffer.
+func NewBitReader(buffer *Buffer, order BitOrder) *BitReader {
+	return &BitReader{buffer: buffer, order: order}
+}
+
+// Reads n bits, n <= 64. With MSBFirst the first bit read is the most significant
+// bit of the value, with LSBFirst it is the least significant bit.
+// Returns an error and reads nothing if fewer than n bits remain or reading fails.
+func (r *BitReader) ReadBits(n uint) (uint64, error) {
+	if n > 64 {
+		return 0, errors.New("Can not read more than 64 bits")
+	}
+	if uint64(n) > uint64(r.nbits)+8*uint64(r.buffer.Remaining()) {
+		return 0, fmt.Errorf("No more bits in buffer: %w", ErrBufferEmpty)
+	}
+	position, cur, nbits := r.buffer.position, r.cur, r.nbits
+	var v uint64
+	var shift uint
+	for n > 0 {
+		if r.nbits == 0 {
+			c, err := r.buffer.ReadByte()
+			if err != nil {
+				r.buffer.position, r.cur, r.nbits = position, cur, nbits
+				return 0, err
+			}
+			r.cur = c
+			r.nbits = 8
+		}
+		take := min(n, r.nbits)
+		mask := uint64(1)<<take - 1
+		if r.order == MSBFirst {
+			v = v<<take | uint64(r.cur>>(r.nbits-take))&mask
+		} else {
+			v |= (uint64(r.cur>>(8-r.nbits)) & mask) << shift
+			shift += take
+		}
+		r.nbits -= take
+		n -= take
+	}
+	return v, nil
+}
+
+// Discards the unread bits of the current byte, so the next read starts at a byte boundary.
+func (r *BitReader) Align() {
+	r.nbits = 0
+}
+
+// BitWriter writes fields of up to 64 bits not aligned to bytes to a Buffer.
+// Flush must be called to write a partial final byte.
+type BitWriter struct {
+	buffer *Buffer
+	order  BitOrder
+	cur    byte
+	nbits  uint // Written bits in cur.
+}
+
+// Creates a bit writer writing at the position of buffer.
+func NewBitWriter(buffer *Buffer, order BitOrder) *BitWriter {
+	return &BitWriter{buffer: buffer, order: order}
+}
+
+// Writes the n low bits of value, n <= 64. With MSBFirst the most significant of
+// the n bits is written first, with LSBFirst the least significant bit.
+// Returns an error and writes nothing if there is not space enough or writing fails.
+func (w *BitWriter) WriteBits(value uint64, n uint) error {
+	if n > 64 {
+		return errors.New("Can not write more than 64 bits")
+	}
+	if !w.buffer.grow(int((w.nbits + n) / 8)) {
+		return w.buffer.growError(ErrBufferFull)
+	}
+	position, cur, nbits := w.buffer.writePosition(), w.cur, w.nbits
+	for n > 0 {
+		take := min(n, 8-w.nbits)
+		mask := uint64(1)<<take - 1
+		if w.order == MSBFirst {
+			w.cur |= byte((value>>(n-take))&mask) << (8 - w.nbits - take)
+		} else {
+			w.cur |= byte(value&mask) << w.nbits
+			value >>= take
+		}
+		w.nbits += take
+		n -= take
+		if w.nbits == 8 {
+			if err := w.buffer.WriteByte(w.cur); err != nil {
+				w.buffer.setWritePosition(position)
+				w.cur, w.nbits = cur, nbits
+				return err
+			}
+			w.cur = 0
+			w.nbits = 0
+		}
+	}
+	return nil
+}
+
+// Writes a partial final byte padded with zero bits, if there is one.
+func (w *BitWriter) Flush() error {
+	if w.nbits == 0 {
+		return nil
+	}
+	if err := w.buffer.WriteByte(w.cur); err != nil {
+		return err
+	}
+	w.cur = 0
+	w.nbits = 0
+	return nil
+}
@@ -0,0 +1,98 @@
+package altdata
+
+import "testing"
+
+func TestBitsMSBFirst(t *testing.T) {
+	b := NewBuffer(4)
+	w := NewBitWriter(b, MSBFirst)
+	w.WriteBits(1, 1)
+	w.WriteBits(0x2, 3)
+	w.WriteBits(0xab, 8)
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	b.Flip()
+	// 1 010 1010 1011 0000, the partial byte is padded with zeros.
+	if string(b.Bytes()) != "\xaa\xb0" {
+		t.Fatalf("% x", b.Bytes())
+	}
+	r := NewBitReader(b, MSBFirst)
+	if v, err := r.ReadBits(4); v != 0xa || err != nil {
+		t.Fatal(v, err)
+	}
+	if v, err := r.ReadBits(8); v != 0xab || err != nil {
+		t.Fatal(v, err)
+	}
+}
+
+func TestBitsLSBFirst(t *testing.T) {
+	b := NewBuffer(4)
+	w := NewBitWriter(b, LSBFirst)
+	w.WriteBits(1, 1)
+	w.WriteBits(0x2, 3)
+	w.WriteBits(0xab, 8)
+	w.Flush()
+	b.Flip()
+	// The first bit goes to bit 0 of the first byte.
+	if string(b.Bytes()) != "\xb5\x0a" {
+		t.Fatalf("% x", b.Bytes())
+	}
+	r := NewBitReader(b, LSBFirst)
+	if v, err := r.ReadBits(1); v != 1 || err != nil {
+		t.Fatal(v, err)
+	}
+	if v, err := r.ReadBits(3); v != 2 || err != nil {
+		t.Fatal(v, err)
+	}
+	if v, err := r.ReadBits(8); v != 0xab || err != nil {
+		t.Fatal(v, err)
+	}
+}
+
+func TestBitsUnderflow(t *testing.T) {
+	b := Wrap([]byte{0xff})
+	r := NewBitReader(b, MSBFirst)
+	r.ReadBits(3)
+	if _, err := r.ReadBits(6); err == nil || b.Position() != 1 {
+		t.Fatal(err)
+	}
+	if v, err := r.ReadBits(5); v != 0x1f || err != nil {
+		t.Fatal("a failed read should not consume bits", v, err)
+	}
+	if _, err := r.ReadBits(65); err == nil {
+		t.Fatal("more than 64 bits should fail")
+	}
+}
+
+func TestBitsPartialFlush(t *testing.T) {
+	b := NewBuffer(1)
+	w := NewBitWriter(b, MSBFirst)
+	if err := w.WriteBits(0x5, 3); err != nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+	if err := w.WriteBits(0x3fff, 14); err == nil {
+		t.Fatal("WriteBits past the capacity should fail")
+	}
+	w.Flush()
+	if b.data[0] != 0xa0 || b.Position() != 1 {
+		t.Fatalf("% x", b.data)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal("Flush without bits should do nothing", err)
+	}
+}
+
+func TestBitsModeError(t *testing.T) {
+	b := NewBuffer(2)
+	b.SetStrict(true)
+	r := NewBitReader(b, MSBFirst)
+	if _, err := r.ReadBits(4); err == nil {
+		t.Fatal("ReadBits should report the mode error")
+	}
+	b.Flip()
+	b.SetManual(0, 2)
+	w := NewBitWriter(b, MSBFirst)
+	if err := w.WriteBits(0xffff, 16); err == nil || b.Position() != 0 {
+		t.Fatal("WriteBits should report the mode error", err)
+	}
+}
@@ -1,17 +1,57 @@
 package altdata
 
+import "bytes"
 import "encoding/binary"
 import "io"
 import "errors"
+import "fmt"
+import "hash"
 
 // Buffer inspired by the Java ByteBuffer for simpler data serialization.
+//
+// When writing, data is stored at the position, which moves towards the limit.
+// Clear sets the position to zero and the limit to the capacity, so
+// WritableRemaining is the space left. Flip then sets the limit to the
+// position and the position to zero, so the written data can be read and
+// Remaining is the number of bytes left to read.
 type Buffer struct {
 	data     []byte
 	position int
 	limit    int
 	order    binary.ByteOrder
+	mark     int
+	unread   int
+	runeSize int
+	growable bool
+	append   bool
+	strict   bool
+	mode     Mode
+	// Growth policy set by SetGrowthPolicy, zero means default.
+	growthFactor float64
+	maxCapacity  int
+	prefix       LengthPrefix
+	readTee      io.Writer
+	checksum     func([]byte) uint32
+	hmacHash     func() hash.Hash
+	err          error
+	// Positions saved by Begin.
+	checkpoints []int
 }
 
+// Errors returned by Buffer methods, comparable with errors.Is.
+var (
+	// Reading past the limit.
+	ErrBufferEmpty = errors.New("No more bytes in buffer")
+	// Writing past the limit of a buffer that can not grow.
+	ErrBufferFull = errors.New("No more space in buffer")
+	// Position, limit or offset outside the buffer.
+	ErrOutOfRange = errors.New("Buffer index out of range")
+	// Writes that could not grow the buffer past the max capacity set with SetGrowthPolicy.
+	ErrMaxCapacity = errors.New("Buffer max capacity exceeded")
+	// Wraps a panic recovered by Safe.
+	ErrPanic = errors.New("Buffer operation panicked")
+)
+
 // Creates a new buffer with given capacity. Default byte order is LittleEndian.
 func NewBuffer(capacity int) *Buffer {
 	buffer := new(Buffer)
@@ -21,14 +61,204 @@ func NewBuffer(capacity int) *Buffer {
 	return buffer
 }
 
+// Creates a new buffer with given capacity and byte order.
+func NewBufferWithOrder(capacity int, order binary.ByteOrder) *Buffer {
+	buffer := NewBuffer(capacity)
+	buffer.order = order
+	return buffer
+}
+
+// Creates a new buffer with given capacity and BigEndian byte order.
+func NewBufferBE(capacity int) *Buffer {
+	return NewBufferWithOrder(capacity, binary.BigEndian)
+}
+
+// Creates a new buffer with given capacity and LittleEndian byte order.
+func NewBufferLE(capacity int) *Buffer {
+	return NewBufferWithOrder(capacity, binary.LittleEndian)
+}
+
+// Creates a new growable buffer with given initial capacity.
+// Writes that would overflow the buffer will resize it instead of truncating.
+func NewGrowableBuffer(initial int) *Buffer {
+	buffer := NewBuffer(initial)
+	buffer.growable = true
+	return buffer
+}
+
+// Creates a new empty buffer in append mode with given initial capacity, see SetAppendMode.
+func NewAppendBuffer(initial int) *Buffer {
+	buffer := NewBuffer(initial)
+	buffer.SetAppendMode(true)
+	return buffer
+}
+
+// Creates a buffer using data as its backing array, ready for reading.
+// The buffer aliases data, so changes through either are visible in both.
+func Wrap(data []byte) *Buffer {
+	return WrapRange(data, 0, len(data))
+}
+
+// Creates a buffer using data as its backing array with given position and limit.
+// The buffer aliases data, so changes through either are visible in both.
+// Will panic if position < 0, position > limit or limit > len(data).
+func WrapRange(data []byte, position, limit int) *Buffer {
+	buffer := new(Buffer)
+	buffer.data = data[:len(data):len(data)]
+	buffer.order = binary.LittleEndian
+	buffer.mark = -1
+	buffer.mode = ModeRead
+	buffer.SetManual(position, limit)
+	return buffer
+}
+
+// Replaces the backing array with data, like Wrap but reusing the Buffer.
+// The buffer aliases data and is ready for reading. All settings are kept, like
+// byte order, growable, append, strict, length prefix, read tee, checksum and
+// growth policy. Position, limit, mark, checkpoints and errors are reset to data.
+func (b *Buffer) Rewrap(data []byte) {
+	b.data = data[:len(data):len(data)]
+	b.position = 0
+	b.limit = len(data)
+	b.mark = -1
+	b.unread = 0
+	b.mode = ModeRead
+	b.err = nil
+	b.checkpoints = b.checkpoints[:0]
+}
+
+// Returns a copy of the buffer with its own backing array.
+// Position, limit, mark, order and growable are preserved.
+func (b *Buffer) Clone() *Buffer {
+	clone := b.Duplicate()
+	clone.data = make([]byte, len(b.data))
+	copy(clone.data, b.data)
+	return clone
+}
+
+// Returns a new buffer SHARING the backing array of b, with its own position and limit.
+// Writes through one are visible in the other, until either is resized.
+func (b *Buffer) Duplicate() *Buffer {
+	duplicate := *b
+	duplicate.checkpoints = append([]int(nil), b.checkpoints...)
+	return &duplicate
+}
+
+// Returns a new buffer over the absolute range [start:end] of the backing array,
+// SHARING it with b. Writes through one are visible in the other.
+// The slice has position 0, limit and capacity end-start, the order of b,
+// and is not growable. Will panic if start > end or end > capacity.
+func (b *Buffer) Slice(start, end int) *Buffer {
+	if start < 0 || start > end || end > cap(b.data) {
+		panic("Buffer slice out of range!")
+	}
+	slice := Wrap(b.data[start:end])
+	slice.order = b.order
+	return slice
+}
+
+// Returns a new buffer with the remaining bytes of all bufs copied in order,
+// flipped and ready to read. It has the byte order of the first buffer.
+// DOES NOT move the positions of bufs.
+func Concat(bufs ...*Buffer) *Buffer {
+	size := 0
+	for _, buf := range bufs {
+		size += buf.Remaining()
+	}
+	result := NewBuffer(size)
+	if len(bufs) > 0 {
+		result.order = bufs[0].order
+	}
+	for _, buf := range bufs {
+		result.Write(buf.Bytes())
+	}
+	result.Flip()
+	return result
+}
+
+// Returns the size of the backing array. The quantities relate like this:
+//
+//	0        position         limit        capacity
+//	|-----------|---------------|--------------|
+//	            |<-- Length --->|
+//	            |<-- CapacityRemaining ------->|
+//	|<----------------- Capacity ------------->|
+//
+// Length, same as Remaining, is what can be read, or written to before the limit.
+// CapacityRemaining, same as WritableRemaining, is what fits before the backing
+// array is exhausted, regardless of the limit.
 func (b *Buffer) Capacity() int {
 	return cap(b.data)
 }
 
+// Returns limit minus position, see Capacity.
 func (b *Buffer) Length() int {
 	return b.limit - b.position
 }
 
+// Returns the number of bytes between position and limit, same as Length.
+func (b *Buffer) Remaining() int {
+	return b.limit - b.position
+}
+
+// Reports whether there are any bytes between position and limit.
+func (b *Buffer) HasRemaining() bool {
+	return b.position < b.limit
+}
+
+// Returns the number of bytes that can be written before the capacity is reached,
+// capacity minus position. Use it while writing, and Remaining while reading.
+func (b *Buffer) WritableRemaining() int {
+	return cap(b.data) - b.position
+}
+
+// Returns capacity minus position, same as WritableRemaining, see Capacity.
+func (b *Buffer) CapacityRemaining() int {
+	return cap(b.data) - b.position
+}
+
+func (b *Buffer) Position() int {
+	return b.position
+}
+
+func (b *Buffer) Limit() int {
+	return b.limit
+}
+
+// Returns position and limit, which can be restored with SetManual.
+func (b *Buffer) State() (position, limit int) {
+	return b.position, b.limit
+}
+
+// Same as State, paired with Restore for reading back written data:
+//
+//	pos, lim := b.Snapshot()
+//	b.Flip()
+//	header := b.Next(4)
+//	b.Restore(pos, lim)
+//	// continue writing after the header
+//
+// ReadbackRange does the same without moving anything.
+func (b *Buffer) Snapshot() (position, limit int) {
+	return b.State()
+}
+
+// Restores position and limit saved by Snapshot.
+// Will panic if position < 0, position > limit or limit > capacity!
+func (b *Buffer) Restore(position, limit int) {
+	b.SetManual(position, limit)
+}
+
+// Returns the already written bytes in the absolute range [start:end],
+// without moving the write cursor. The returned slice aliases the buffer.
+// Will panic if start > end or end is past the written data!
+func (b *Buffer) ReadbackRange(start, end int) []byte {
+	if start < 0 || start > end || end > b.writePosition() {
+		panic("Buffer range out of range!")
+	}
+	return b.data[start:end:end]
+}
+
 func (b *Buffer) Resize(newSize int) {
 	newSlice := make([]byte, newSize)
 	copy(newSlice, b.data)
@@ -39,83 +269,641 @@ func (b *Buffer) Resize(newSize int) {
 	if b.position > newSize {
 		b.position = newSize
 	}
+	b.checkMark()
+}
+
+// Reallocates the backing array to exactly limit bytes, releasing the capacity
+// beyond it. Data, position and limit are preserved. No-op if capacity equals limit.
+func (b *Buffer) ShrinkToFit() {
+	if b.limit < cap(b.data) {
+		b.Resize(b.limit)
+	}
+}
+
+// Sets limit to n, discarding everything beyond it. DOES NOT reallocate like Resize.
+// Returns an error if n < position or n > capacity.
+func (b *Buffer) Truncate(n int) error {
+	if n < b.position || n > cap(b.data) {
+		return ErrOutOfRange
+	}
+	b.limit = n
+	b.checkMark()
+	return nil
+}
+
+// Enable or disable growing on writes that exceed the limit.
+// When disabled writes are truncated to the remaining space. Default is false.
+func (b *Buffer) SetGrowable(growable bool) {
+	b.growable = growable
 }
 
-// Implementing io.Reader.
+// Sets how growable and append mode buffers grow. Capacity is multiplied by
+// factor when more room is needed, but never beyond max. Writes that do not fit
+// in max are truncated like a non-growable buffer and return ErrMaxCapacity.
+// A max of zero or less means no limit. Default is factor 2 and no limit.
+// Will panic if factor is not larger than 1!
+func (b *Buffer) SetGrowthPolicy(factor float64, max int) {
+	if !(factor > 1) {
+		panic("Buffer growth factor must be larger than 1!")
+	}
+	b.growthFactor = factor
+	b.maxCapacity = max
+}
+
+// Enable or disable append mode, making the Buffer work like a bytes.Buffer.
+// In append mode writes are added at the limit instead of the position,
+// growing the buffer as needed, so written data can be read right away from
+// the position without calling Flip. Enabling it in write mode sets the limit to
+// the position and the position to zero, so data written before is kept. In read
+// mode position and limit already mark the unread data and are kept.
+// Flip should not be used in append mode, as it discards unread data, and Clear
+// empties the buffer by setting both position and limit to zero.
+// Disabling it leaves position and limit as they are, around the unread data,
+// and sets ModeRead.
+func (b *Buffer) SetAppendMode(append bool) {
+	if !append && b.append {
+		b.mode = ModeRead
+	}
+	if append && !b.append && b.mode == ModeWrite {
+		b.limit = b.position
+		b.position = 0
+		b.checkMark()
+	}
+	b.append = append
+}
+
+// Returns where the next write starts, the limit in append mode otherwise the position.
+func (b *Buffer) writePosition() int {
+	if b.append {
+		return b.limit
+	}
+	return b.position
+}
+
+// Moves the write position back to a value from writePosition, undoing a write.
+func (b *Buffer) setWritePosition(position int) {
+	if b.append {
+		b.limit = position
+	} else {
+		b.position = position
+	}
+}
+
+// Makes room for n bytes at the write position if the buffer is growable or in append mode.
+// Capacity grows by the growth factor and limit is extended to the new capacity.
+// Returns false if there is still not enough room.
+func (b *Buffer) grow(n int) bool {
+	if b.strict {
+		b.check()
+	}
+	if b.append {
+		return b.ensureCapacity(b.limit + n)
+	}
+	if b.position+n <= b.limit {
+		return true
+	}
+	if !b.growable {
+		return false
+	}
+	ok := b.ensureCapacity(b.position + n)
+	b.limit = cap(b.data)
+	return ok
+}
+
+// Returns ErrMaxCapacity if the buffer could have grown but for the max capacity, otherwise err.
+func (b *Buffer) growError(err error) error {
+	if (b.growable || b.append) && b.maxCapacity > 0 {
+		return ErrMaxCapacity
+	}
+	return err
+}
+
+// Ensures n bytes can be written after the write position, the limit in append mode,
+// resizing by the growth factor if needed. Data, position and order are preserved.
+// In write mode a limit at the old capacity is moved to the new capacity so n bytes
+// can be written. In read mode the limit is the end of the data and is kept.
+// Will panic if n is negative or the max capacity is exceeded!
+func (b *Buffer) Grow(n int) {
+	if n < 0 {
+		panic("Tryed to grow Buffer by negative number of bytes")
+	}
+	size := cap(b.data)
+	if !b.ensureCapacity(b.writePosition() + n) {
+		panic("Buffer max capacity exceeded!")
+	}
+	if b.limit == size && b.mode == ModeWrite && !b.append {
+		b.limit = cap(b.data)
+	}
+}
+
+// Resizes by the growth factor if capacity is less than size, but not beyond max capacity.
+// Returns false if capacity is still less than size.
+func (b *Buffer) ensureCapacity(size int) bool {
+	if size <= cap(b.data) {
+		return true
+	}
+	factor := b.growthFactor
+	if factor == 0 {
+		factor = 2
+	}
+	newSize := int(float64(cap(b.data)) * factor)
+	if newSize < size {
+		newSize = size
+	}
+	if b.maxCapacity > 0 && newSize > b.maxCapacity {
+		newSize = b.maxCapacity
+	}
+	if newSize > cap(b.data) {
+		b.Resize(newSize)
+	}
+	return size <= cap(b.data)
+}
+
+// Returns the next n bytes and moves the position past them.
+// Returns an error and does not move if fewer than n bytes remain.
+func (b *Buffer) read(n int) ([]byte, error) {
+	if b.strict {
+		b.check()
+	}
+	if err := b.checkMode(ModeRead); err != nil {
+		return nil, err
+	}
+	if n > b.limit-b.position {
+		return nil, ErrBufferEmpty
+	}
+	b.position += n
+	b.unread = 0
+	b.tee(b.data[b.position-n : b.position])
+	return b.data[b.position-n : b.position], nil
+}
+
+// Returns room for up to n bytes at the write position and moves past it, growing if allowed.
+func (b *Buffer) space(n int) []byte {
+	if b.strict {
+		b.check()
+	}
+	b.grow(n)
+	if b.append {
+		n = min(n, cap(b.data)-b.limit)
+		b.limit += n
+		return b.data[b.limit-n : b.limit]
+	}
+	n = min(n, b.limit-b.position)
+	b.position += n
+	return b.data[b.position-n : b.position]
+}
+
+// Returns n bytes to write into and moves the write position past them, growing if allowed.
+// Returns an error and does not move if there is not space enough.
+func (b *Buffer) reserve(n int) ([]byte, error) {
+	if err := b.checkMode(ModeWrite); err != nil {
+		return nil, err
+	}
+	if !b.grow(n) {
+		return nil, b.growError(ErrBufferFull)
+	}
+	return b.space(n), nil
+}
+
+// Implementing io.Reader. Returns io.EOF when no bytes remain.
 func (b *Buffer) Read(p []byte) (n int, err error) {
+	if b.strict {
+		b.check()
+	}
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, err
+	}
+	if b.position >= b.limit {
+		return 0, io.EOF
+	}
 	n = copy(p, b.data[b.position:b.limit])
 	b.position += n
+	b.unread = 0
+	b.tee(p[:n])
 	return
 }
 
+// Reads exactly len(p) bytes into p.
+// Returns io.ErrUnexpectedEOF and does not move if fewer bytes remain.
+func (b *Buffer) ReadFull(p []byte) error {
+	data, err := b.read(len(p))
+	if err == ErrBufferEmpty {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	copy(p, data)
+	return nil
+}
+
 // Read a single byte if possible
 func (b *Buffer) ReadByte() (byte, error) {
+	if b.strict {
+		b.check()
+	}
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, err
+	}
 	if b.position >= b.limit {
-		return 0, errors.New("No more bytes in buffer")
+		return 0, ErrBufferEmpty
 	}
 	b.position++
+	b.unread = b.position
+	b.runeSize = 1
+	b.tee(b.data[b.position-1 : b.position])
 	return b.data[b.position-1], nil
 }
 
-// Read as many bytes as possible from reader.
-// Returns bytes read as int64 but is int32, error.
+// Implementing io.ByteScanner. Moves the position back one byte.
+// Returns an error if the previous operation was not a successful ReadByte or ReadRune.
+func (b *Buffer) UnreadByte() error {
+	if b.unread == 0 || b.unread != b.position {
+		return errors.New("Previous operation was not ReadByte or ReadRune")
+	}
+	b.position--
+	b.unread = 0
+	return nil
+}
+
+// Implementing io.ReaderFrom. Reads from reader until the buffer is full or
+// reader returns io.EOF, growing if growable. Returns ErrMaxCapacity if the growth
+// policy stopped it before io.EOF. Like Write the data is stored
+// from the position and the position is moved past it, so call Flip to read it.
+// In append mode the data is added at the limit instead.
+// io.EOF is not returned as an error.
 func (b *Buffer) ReadFrom(reader io.Reader) (n int64, err error) {
-	r, err := reader.Read(b.Bytes())
-	b.position += r
-	n = int64(r)
-	return
+	if err := b.checkMode(ModeWrite); err != nil {
+		return 0, err
+	}
+	for empty := 0; ; {
+		start := b.writePosition()
+		end := b.limit
+		if b.append {
+			end = cap(b.data)
+		}
+		if start == end {
+			if size := cap(b.data); !b.grow(512) && cap(b.data) == size {
+				return n, b.growError(nil)
+			}
+			continue
+		}
+		r, err := reader.Read(b.data[start:end])
+		b.setWritePosition(start + r)
+		n += int64(r)
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		if r > 0 {
+			empty = 0
+		} else if empty++; empty >= 100 {
+			return n, io.ErrNoProgress
+		}
+	}
 }
 
 // Implementing io.Writer.
+// Grows the buffer if growable, otherwise p is truncated to the remaining space
+// and io.ErrShortWrite is returned, or ErrMaxCapacity if the growth policy stopped it.
 func (b *Buffer) Write(p []byte) (n int, err error) {
-	n = copy(b.data[b.position:b.limit], p)
-	b.position += n
+	if err := b.checkMode(ModeWrite); err != nil {
+		return 0, err
+	}
+	n = copy(b.space(len(p)), p)
+	if n < len(p) {
+		err = b.growError(io.ErrShortWrite)
+	}
 	return
 }
 
 // Write a single byte to the Buffer is there is space enough
 func (b *Buffer) WriteByte(in byte) error {
-	if b.position >= b.limit {
-		return errors.New("No more space in buffer")
+	p, err := b.reserve(1)
+	if err != nil {
+		return err
 	}
-	b.data[b.position] = in
-	b.position++
+	p[0] = in
 	return nil
 }
 
-// Write as many bytes as possible to writer.
-// Returns bytes written as int64 but is int32, error.
+// Writes n copies of value, growing or truncating like Write.
+// Returns bytes written and io.ErrShortWrite if truncated.
+func (b *Buffer) Fill(value byte, n int) (int, error) {
+	if err := b.checkMode(ModeWrite); err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.New("Tryed to fill negative number of bytes in Buffer")
+	}
+	p := b.space(n)
+	for i := range p {
+		p[i] = value
+	}
+	if len(p) < n {
+		return len(p), b.growError(io.ErrShortWrite)
+	}
+	return n, nil
+}
+
+// Copies up to n remaining bytes of src to the position, growing or truncating like Write.
+// Both positions are moved by the number of bytes copied.
+// Returns io.ErrShortWrite if fewer bytes were copied than available in src.
+func (b *Buffer) CopyFrom(src *Buffer, n int) (int, error) {
+	if err := b.checkMode(ModeWrite); err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.New("Tryed to copy negative number of bytes to Buffer")
+	}
+	if n > src.limit-src.position {
+		n = src.limit - src.position
+	}
+	c := copy(b.space(n), src.data[src.position:src.position+n])
+	src.position += c
+	src.tee(src.data[src.position-c : src.position])
+	if c < n {
+		return c, b.growError(io.ErrShortWrite)
+	}
+	return c, nil
+}
+
+// Writes zeros until the write position is a multiple of n. Returns bytes written.
+func (b *Buffer) Align(n int) (int, error) {
+	if n <= 0 {
+		return 0, errors.New("Alignment must be positive")
+	}
+	return b.Fill(0, (n-b.writePosition()%n)%n)
+}
+
+// Skips bytes until position is a multiple of n.
+// Returns an error and does not move if the boundary is beyond the limit.
+func (b *Buffer) AlignRead(n int) error {
+	if n <= 0 {
+		return errors.New("Alignment must be positive")
+	}
+	return b.Skip((n - b.position%n) % n)
+}
+
+// Implementing io.ReaderAt. Offset is from the start of the backing array
+// and DOES NOT depend on or move the position and limit.
+func (b *Buffer) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("Negative offset for ReadAt")
+	}
+	if off >= int64(len(b.data)) {
+		return 0, io.EOF
+	}
+	n = copy(p, b.data[off:])
+	if n < len(p) {
+		err = io.EOF
+	}
+	return
+}
+
+// Implementing io.WriterAt. Offset is from the start of the backing array
+// and DOES NOT depend on or move the position and limit.
+// Returns io.ErrShortWrite if p does not fit in the capacity.
+func (b *Buffer) WriteAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("Negative offset for WriteAt")
+	}
+	if off < int64(len(b.data)) {
+		n = copy(b.data[off:], p)
+	}
+	if n < len(p) {
+		err = io.ErrShortWrite
+	}
+	return
+}
+
+// Implementing io.WriterTo. Writes the remaining bytes to writer, calling
+// Write until they are all written or writer returns an error.
 func (b *Buffer) WriteTo(writer io.Writer) (n int64, err error) {
-	r, err := writer.Write(b.Bytes())
-	b.position += r
-	n = int64(r)
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, err
+	}
+	for b.position < b.limit {
+		var r int
+		r, err = writer.Write(b.Bytes())
+		b.tee(b.data[b.position : b.position+r])
+		b.position += r
+		n += int64(r)
+		if err != nil {
+			return
+		}
+		if r == 0 {
+			return n, io.ErrShortWrite
+		}
+	}
 	return
 }
 
 // Sets capacity to current position and position to zero.
 // Should be called before reading data from the Buffer.
+// Discards the mark and checkpoints.
 func (b *Buffer) Flip() {
 	b.limit = b.position
 	b.position = 0
+	b.mark = -1
+	b.unread = 0
+	b.mode = ModeRead
+	b.checkpoints = b.checkpoints[:0]
 }
 
-// Reset position to zero.
+// Reset position to zero. Discards the mark.
 func (b *Buffer) Rewind() {
 	b.position = 0
+	b.mark = -1
+	b.unread = 0
 }
 
 // Sets position to zero and limit to capacity.
 // Should be called before starting to write data to the Buffer.
+// Discards the mark, checkpoints and the error from the Put methods.
+// In append mode the limit is set to zero, emptying the buffer.
 func (b *Buffer) Clear() {
 	b.limit = cap(b.data)
+	if b.append {
+		b.limit = 0
+	}
 	b.position = 0
+	b.mark = -1
+	b.unread = 0
+	b.mode = ModeWrite
+	b.err = nil
+	b.checkpoints = b.checkpoints[:0]
+}
+
+// Moves the remaining bytes to the start of the Buffer.
+// Position is set to the number of bytes moved and limit to capacity,
+// so more data can be written after the unread bytes. Discards the mark.
+// In append mode position is set to zero and limit to the number of bytes moved.
+func (b *Buffer) Compact() {
+	if b.strict {
+		b.check()
+	}
+	n := copy(b.data, b.data[b.position:b.limit])
+	b.limit = cap(b.data)
+	b.position = n
+	if b.append {
+		b.limit = n
+		b.position = 0
+	}
+	b.mark = -1
+	b.unread = 0
+	b.mode = ModeWrite
+}
+
+// Remember the current position, so it can be restored by Reset.
+func (b *Buffer) Mark() {
+	b.mark = b.position
+}
+
+// Set position to the previously marked position.
+// Will panic if no mark is set!
+func (b *Buffer) Reset() {
+	if b.mark < 0 {
+		panic("Buffer mark not set!")
+	}
+	b.position = b.mark
+	b.unread = 0
+}
+
+// Pushes the current position as a checkpoint. Checkpoints can be nested,
+// each Begin must be followed by either Commit or Rollback.
+func (b *Buffer) Begin() {
+	b.checkpoints = append(b.checkpoints, b.position)
+}
+
+// Discards the latest checkpoint, keeping the current position.
+// Will panic if there is no checkpoint!
+func (b *Buffer) Commit() {
+	b.popCheckpoint()
+}
+
+// Sets position to the latest checkpoint and discards it.
+// Will panic if there is no checkpoint or it is beyond the limit!
+func (b *Buffer) Rollback() {
+	position := b.popCheckpoint()
+	if position > b.limit {
+		panic("Buffer checkpoint out of range!")
+	}
+	b.position = position
+	b.unread = 0
+	b.checkMark()
+}
+
+// Runs fn and moves the position back to where it was if fn returns an error,
+// so a group of reads either all succeed or leave the position unchanged.
+// Returns the error from fn.
+func (b *Buffer) ReadAtomic(fn func(*Buffer) error) error {
+	position := b.position
+	err := fn(b)
+	if err != nil {
+		b.position = min(position, b.limit)
+		b.unread = 0
+		b.checkMark()
+	}
+	return err
+}
+
+func (b *Buffer) popCheckpoint() int {
+	if len(b.checkpoints) == 0 {
+		panic("Buffer has no checkpoint!")
+	}
+	position := b.checkpoints[len(b.checkpoints)-1]
+	b.checkpoints = b.checkpoints[:len(b.checkpoints)-1]
+	return position
+}
+
+// Discards the mark if it is beyond the current position or limit.
+func (b *Buffer) checkMark() {
+	if b.mark > b.position || b.mark > b.limit {
+		b.mark = -1
+	}
 }
 
 // Returns remaining bytes. DOES NOT move the position.
+// The slice ALIASES the backing array, so it changes with the buffer and is
+// not updated by Resize or growing. Use BytesCopy to keep the bytes.
 func (b *Buffer) Bytes() []byte {
+	if b.strict {
+		b.check()
+	}
 	return b.data[b.position:b.limit]
 }
 
+// Returns a copy of the remaining bytes. DOES NOT move the position.
+func (b *Buffer) BytesCopy() []byte {
+	return bytes.Clone(b.Bytes())
+}
+
+// Returns an independent reader over the remaining bytes, with its own offset.
+// Reading from it DOES NOT move the position of the Buffer.
+func (b *Buffer) Reader() io.ReadSeeker {
+	return bytes.NewReader(b.Bytes())
+}
+
+// Returns the buffer as a plain io.Writer. In write mode, as after NewBuffer or Clear,
+// and in append mode the writer is the same as Write, so Flip gives the data.
+// In read mode, as after Flip or Wrap, it adds data at the limit without moving
+// the position, so the data can be read right away. The buffer grows if it is
+// growable, otherwise data is truncated to the capacity and io.ErrShortWrite is returned.
+// In strict mode writing in read mode returns an error, like Write.
+func (b *Buffer) Writer() io.Writer {
+	return &appendWriter{b}
+}
+
+type appendWriter struct {
+	buffer *Buffer
+}
+
+func (w *appendWriter) Write(p []byte) (int, error) {
+	b := w.buffer
+	if b.append || b.mode == ModeWrite {
+		return b.Write(p)
+	}
+	if b.strict {
+		return 0, fmt.Errorf("Buffer.Writer called in %s mode", b.mode)
+	}
+	if b.growable {
+		b.ensureCapacity(b.limit + len(p))
+	}
+	n := copy(b.data[b.limit:cap(b.data)], p)
+	b.limit += n
+	if n < len(p) {
+		return n, b.growError(io.ErrShortWrite)
+	}
+	return n, nil
+}
+
+// Returns a reader over the next n remaining bytes that returns io.EOF after them.
+// Unlike Reader it reads from b, moving its position by the bytes read, so after
+// reading to io.EOF the position is moved by n. Will panic if n is negative!
+func (b *Buffer) LimitReader(n int) io.Reader {
+	if n < 0 {
+		panic("Tryed to read negative number of bytes from Buffer")
+	}
+	return &limitReader{b, min(n, b.limit-b.position)}
+}
+
+type limitReader struct {
+	buffer *Buffer
+	n      int
+}
+
+func (r *limitReader) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		return 0, io.EOF
+	}
+	n, err := r.buffer.Read(p[:min(len(p), r.n)])
+	r.n -= n
+	return n, err
+}
+
 // Change the position relatively from its current value.
 // Will panic if the changed position is < 0 or > limit!
 func (b *Buffer) ChangePosition(n int) {
@@ -123,42 +911,232 @@ func (b *Buffer) ChangePosition(n int) {
 		panic("Buffer position out of range!")
 	}
 	b.position += n
+	b.unread = 0
+	b.checkMark()
+}
+
+// Move the position n bytes, negative n moves backwards.
+// Returns an error and does not move if the new position is < 0 or > limit.
+func (b *Buffer) Skip(n int) error {
+	if b.position+n < 0 || b.position+n > b.limit {
+		return ErrOutOfRange
+	}
+	b.position += n
+	b.unread = 0
+	b.checkMark()
+	return nil
+}
+
+// Moves the position up to n bytes forward, like bufio.Reader.Discard.
+// Returns the number of bytes discarded, and io.EOF if fewer than n remained.
+func (b *Buffer) Discard(n int) (discarded int, err error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.New("Tryed to discard negative number of bytes from Buffer")
+	}
+	discarded = len(b.Next(n))
+	if discarded < n {
+		err = io.EOF
+	}
+	return
+}
+
+// Non-panicking version of ChangePosition, same as Skip.
+func (b *Buffer) TryChangePosition(n int) error {
+	return b.Skip(n)
 }
 
 // Set absolute position and limit manually.
-// Will panic if position > limit or limit > capacity.
+// Will panic if position < 0, position > limit or limit > capacity.
 func (b *Buffer) SetManual(position, limit int) {
-	if position > limit || limit > cap(b.data) {
+	if position < 0 || position > limit || limit > cap(b.data) {
 		panic("Buffer position or limit out of range!")
 	}
 	b.limit = limit
 	b.position = position
+	b.unread = 0
+	b.checkMark()
+}
+
+// Non-panicking version of SetManual.
+// Returns an error and changes nothing if position > limit or limit > capacity.
+func (b *Buffer) TrySetManual(position, limit int) error {
+	if position < 0 || position > limit || limit > cap(b.data) {
+		return ErrOutOfRange
+	}
+	b.SetManual(position, limit)
+	return nil
+}
+
+// Implementing io.Seeker. SeekEnd is relative to limit.
+// Returns an error if the new position is < 0 or > limit.
+func (b *Buffer) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = int64(b.position) + offset
+	case io.SeekEnd:
+		abs = int64(b.limit) + offset
+	default:
+		return 0, errors.New("Invalid whence for Seek")
+	}
+	if abs < 0 || abs > int64(b.limit) {
+		return 0, ErrOutOfRange
+	}
+	b.position = int(abs)
+	b.unread = 0
+	b.checkMark()
+	return abs, nil
 }
 
-// Returns up to n bytes. Panic if n is negative!
+// Returns up to n bytes. Panic if n is negative, or in write mode when strict!
+// The returned slice SHARES the backing array, so it changes when the buffer is
+// written to and is no longer updated after Resize. Use NextCopy to keep the data.
 func (b *Buffer) Next(n int) []byte {
+	if b.strict {
+		b.check()
+	}
+	if err := b.checkMode(ModeRead); err != nil {
+		panic(err.Error() + "!")
+	}
 	if n < 0 {
 		panic("Tryed to read negative number of bytes from Buffer")
 	}
 
-	if b.position+n > b.limit {
+	if n > b.limit-b.position {
 		n = b.limit - b.position
 	}
 
 	data := b.data[b.position : b.position+n]
 	b.position += n
+	b.unread = 0
+	b.tee(data)
 
 	return data
 }
 
+// Same as Next, but returns a copy that does not share the backing array.
+func (b *Buffer) NextCopy(n int) []byte {
+	return append([]byte{}, b.Next(n)...)
+}
+
+// Non-panicking version of Next. Returns an error if n is negative.
+func (b *Buffer) TryNext(n int) ([]byte, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, errors.New("Tryed to read negative number of bytes from Buffer")
+	}
+	return b.Next(n), nil
+}
+
+// Returns the bytes up to and including the first delim and moves the position past it.
+// Returns io.EOF and does not move if delim is not found before the limit.
+// The returned slice shares the backing array like Next.
+func (b *Buffer) ReadUntil(delim byte) ([]byte, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return nil, err
+	}
+	i := bytes.IndexByte(b.Bytes(), delim)
+	if i < 0 {
+		return nil, io.EOF
+	}
+	return b.Next(i + 1), nil
+}
+
+// Same as ReadUntil, but delim is not included in the returned slice.
+func (b *Buffer) ReadUntilExclusive(delim byte) ([]byte, error) {
+	data, err := b.ReadUntil(delim)
+	if err != nil {
+		return nil, err
+	}
+	return data[:len(data)-1], nil
+}
+
+// Returns the next byte without moving the position.
+func (b *Buffer) PeekByte() (byte, error) {
+	if b.strict {
+		b.check()
+	}
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, err
+	}
+	if b.position >= b.limit {
+		return 0, ErrBufferEmpty
+	}
+	return b.data[b.position], nil
+}
+
+// Returns up to n bytes like Next, but DOES NOT move the position.
+// Returns an error if n is negative or no bytes remain.
+func (b *Buffer) PeekBytes(n int) ([]byte, error) {
+	if b.strict {
+		b.check()
+	}
+	if err := b.checkMode(ModeRead); err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, errors.New("Tryed to peek negative number of bytes from Buffer")
+	}
+	if b.position >= b.limit {
+		return nil, ErrBufferEmpty
+	}
+	if n > b.limit-b.position {
+		n = b.limit - b.position
+	}
+	return b.data[b.position : b.position+n], nil
+}
+
 // Returns a string of length up to n bytes. Panic if n is negative!
 func (b *Buffer) NextString(n int) string {
 	return string(b.Next(n))
 }
 
-// Writes a string to the buffer. Returns n bytes copied.
+// Non-panicking version of NextString for lengths from untrusted data.
+// Returns an error and does not move if n is negative or exceeds the remaining bytes.
+func (b *Buffer) TryNextString(n int) (string, error) {
+	if n < 0 {
+		return "", errors.New("Tryed to read negative number of bytes from Buffer")
+	}
+	p, err := b.read(n)
+	if err != nil {
+		return "", err
+	}
+	return string(p), nil
+}
+
+// Implementing io.StringWriter, growing or truncating like Write without
+// converting str to a byte slice. Returns n bytes copied,
+// and io.ErrShortWrite if the string was truncated.
 func (b *Buffer) WriteString(str string) (n int, err error) {
-	return b.Write([]byte(str))
+	if err := b.checkMode(ModeWrite); err != nil {
+		return 0, err
+	}
+	n = copy(b.space(len(str)), str)
+	if n < len(str) {
+		err = b.growError(io.ErrShortWrite)
+	}
+	return
+}
+
+// Set a writer receiving a copy of all bytes consumed by read methods, nil disables it.
+// Peek methods and position changes like Skip and Seek are not written to it.
+// Bytes of a read that fails and restores the position may still be written.
+// Errors from the writer are ignored.
+func (b *Buffer) SetReadTee(w io.Writer) {
+	b.readTee = w
+}
+
+func (b *Buffer) tee(p []byte) {
+	if b.readTee != nil && len(p) > 0 {
+		b.readTee.Write(p)
+	}
 }
 
 // Default is LittleEndian.
@@ -166,6 +1144,18 @@ func (b *Buffer) SetByteOrder(order binary.ByteOrder) {
 	b.order = order
 }
 
+func (b *Buffer) Order() binary.ByteOrder {
+	return b.order
+}
+
+// Runs fn with the byte order temporarily set to order.
+// The previous order is restored when fn returns or panics.
+func (b *Buffer) WithOrder(order binary.ByteOrder, fn func(*Buffer) error) error {
+	defer b.SetByteOrder(b.order)
+	b.order = order
+	return fn(b)
+}
+
 // Wrapper for encoding/binary.Read.
 func (b *Buffer) ReadBinary(data interface{}) error {
 	return binary.Read(b, b.order, data)
@@ -0,0 +1,1448 @@
+package altdata
+
+import "encoding/json"
+import "errors"
+import "fmt"
+import "io"
+import "strings"
+import "testing"
+
+// Returns the recovered panic value of f, or nil if it did not panic.
+func catchPanic(f func()) (r interface{}) {
+	defer func() { r = recover() }()
+	f()
+	return nil
+}
+
+func TestWrap(t *testing.T) {
+	data := []byte{1, 2, 3}
+	b := Wrap(data)
+	if b.Position() != 0 || b.Limit() != 3 || b.Capacity() != 3 {
+		t.Fatal(b.Position(), b.Limit(), b.Capacity())
+	}
+	data[0] = 9
+	if v, _ := b.ReadByte(); v != 9 {
+		t.Fatal("Wrap should alias data", v)
+	}
+}
+
+func TestWrapRange(t *testing.T) {
+	b := WrapRange([]byte{1, 2, 3, 4}, 1, 3)
+	if string(b.Bytes()) != string([]byte{2, 3}) {
+		t.Fatal(b.Bytes())
+	}
+	for _, r := range [][2]int{{-1, 2}, {3, 2}, {0, 5}} {
+		if catchPanic(func() { WrapRange([]byte{1, 2, 3, 4}, r[0], r[1]) }) == nil {
+			t.Fatal("WrapRange should panic for", r)
+		}
+	}
+}
+
+func TestSetManualNegative(t *testing.T) {
+	b := NewBuffer(4)
+	if catchPanic(func() { b.SetManual(-1, 2) }) == nil {
+		t.Fatal("SetManual should panic on negative position")
+	}
+	if catchPanic(func() { b.Restore(-1, 2) }) == nil {
+		t.Fatal("Restore should panic on negative position")
+	}
+	if b.Position() != 0 || b.Limit() != 4 {
+		t.Fatal(b.Position(), b.Limit())
+	}
+}
+
+func TestMarkReset(t *testing.T) {
+	b := Wrap([]byte{1, 2, 3, 4})
+	b.Skip(1)
+	b.Mark()
+	b.Skip(2)
+	b.Reset()
+	if b.Position() != 1 {
+		t.Fatal(b.Position())
+	}
+	// Reset keeps the mark, so it can be used again.
+	b.Skip(3)
+	b.Reset()
+	if b.Position() != 1 {
+		t.Fatal(b.Position())
+	}
+}
+
+func TestResetWithoutMark(t *testing.T) {
+	b := NewBuffer(4)
+	if catchPanic(b.Reset) == nil {
+		t.Fatal("Reset without Mark should panic")
+	}
+}
+
+func TestMarkDiscardedByFlip(t *testing.T) {
+	b := NewBuffer(4)
+	b.WriteUint16(1)
+	b.Mark()
+	b.Flip()
+	if catchPanic(b.Reset) == nil {
+		t.Fatal("Flip should discard the mark")
+	}
+	b.Mark()
+	b.Rewind()
+	if catchPanic(b.Reset) == nil {
+		t.Fatal("Rewind should discard the mark")
+	}
+	b.Mark()
+	b.Clear()
+	if catchPanic(b.Reset) == nil {
+		t.Fatal("Clear should discard the mark")
+	}
+}
+
+func TestMarkDiscardedByResize(t *testing.T) {
+	b := Wrap(make([]byte, 8))
+	b.Skip(6)
+	b.Mark()
+	b.Resize(4)
+	if catchPanic(b.Reset) == nil {
+		t.Fatal("Resize below the mark should discard it")
+	}
+	b.Mark()
+	b.Resize(8)
+	b.Reset()
+	if b.Position() != 4 {
+		t.Fatal(b.Position())
+	}
+}
+
+func TestCompact(t *testing.T) {
+	b := NewBuffer(6)
+	b.Write([]byte("abcdef"))
+	b.Flip()
+	b.Next(4)
+	b.Compact()
+	if b.Position() != 2 || b.Limit() != 6 {
+		t.Fatal(b.Position(), b.Limit())
+	}
+	b.Write([]byte("gh"))
+	b.Flip()
+	if string(b.Bytes()) != "efgh" {
+		t.Fatal(string(b.Bytes()))
+	}
+}
+
+func TestCompactFull(t *testing.T) {
+	b := Wrap([]byte("abcd"))
+	b.Compact()
+	if b.Position() != 4 || b.Limit() != 4 || string(b.data) != "abcd" {
+		t.Fatal(b.Position(), b.Limit(), string(b.data))
+	}
+	b.Flip()
+	if string(b.Bytes()) != "abcd" {
+		t.Fatal(string(b.Bytes()))
+	}
+}
+
+func TestReadAtWriteAt(t *testing.T) {
+	b := NewBuffer(6)
+	b.Write([]byte("ab"))
+	if n, err := b.WriteAt([]byte("xyz"), 3); n != 3 || err != nil {
+		t.Fatal(n, err)
+	}
+	if b.Position() != 2 || b.Limit() != 6 {
+		t.Fatal(b.Position(), b.Limit())
+	}
+	p := make([]byte, 4)
+	if n, err := b.ReadAt(p, 1); n != 4 || err != nil || string(p) != "b\x00xy" {
+		t.Fatal(n, err, p)
+	}
+	if b.Position() != 2 || b.Limit() != 6 {
+		t.Fatal(b.Position(), b.Limit())
+	}
+}
+
+func TestReadAtEOF(t *testing.T) {
+	b := Wrap([]byte("abc"))
+	p := make([]byte, 2)
+	if n, err := b.ReadAt(p, 2); n != 1 || err != io.EOF || p[0] != 'c' {
+		t.Fatal(n, err)
+	}
+	if n, err := b.ReadAt(p, 3); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+	if _, err := b.ReadAt(p, -1); err == nil {
+		t.Fatal("ReadAt should fail on negative offset")
+	}
+	if n, err := b.WriteAt([]byte("xy"), 2); n != 1 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+}
+
+func TestUnreadByte(t *testing.T) {
+	b := Wrap([]byte{1, 2, 3})
+	if err := b.UnreadByte(); err == nil {
+		t.Fatal("UnreadByte at the start should fail")
+	}
+	c1, _ := b.ReadByte()
+	if err := b.UnreadByte(); err != nil {
+		t.Fatal(err)
+	}
+	c2, _ := b.ReadByte()
+	if c1 != 1 || c2 != 1 || b.Position() != 1 {
+		t.Fatal(c1, c2, b.Position())
+	}
+	if err := b.UnreadByte(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.UnreadByte(); err == nil {
+		t.Fatal("second UnreadByte should fail")
+	}
+}
+
+func TestUnreadByteInvalidated(t *testing.T) {
+	moves := map[string]func(b *Buffer){
+		"Seek":           func(b *Buffer) { b.Next(1); b.Seek(1, io.SeekStart) },
+		"Skip":           func(b *Buffer) { b.Skip(1); b.Skip(-1) },
+		"ChangePosition": func(b *Buffer) { b.ChangePosition(1); b.ChangePosition(-1) },
+		"SetManual":      func(b *Buffer) { b.SetManual(1, 3) },
+		"Next":           func(b *Buffer) { b.Skip(-1); b.Next(1) },
+		"Read":           func(b *Buffer) { b.Skip(-1); b.Read(make([]byte, 1)) },
+		"ReadUint8":      func(b *Buffer) { b.Skip(-1); b.ReadUint8() },
+	}
+	for name, move := range moves {
+		b := Wrap([]byte{1, 2, 3})
+		b.ReadByte()
+		move(b)
+		if b.Position() != 1 {
+			t.Fatal(name, b.Position())
+		}
+		if err := b.UnreadByte(); err == nil {
+			t.Fatal("UnreadByte should fail after", name)
+		}
+	}
+}
+
+func TestDuplicateShares(t *testing.T) {
+	b := Wrap([]byte("abcd"))
+	d := b.Duplicate()
+	d.Skip(2)
+	d.data[0] = 'z'
+	if b.Position() != 0 || string(b.Bytes()) != "zbcd" {
+		t.Fatal(b.Position(), string(b.Bytes()))
+	}
+}
+
+func TestCloneIsolated(t *testing.T) {
+	b := Wrap([]byte("abcd"))
+	b.Skip(1)
+	b.Mark()
+	c := b.Clone()
+	c.data[1] = 'z'
+	c.Skip(1)
+	if string(b.Bytes()) != "bcd" || string(c.Bytes()) != "cd" {
+		t.Fatal(string(b.Bytes()), string(c.Bytes()))
+	}
+	c.Reset()
+	if c.Position() != 1 || c.Order() != b.Order() {
+		t.Fatal(c.Position())
+	}
+}
+
+func TestReadEOF(t *testing.T) {
+	b := Wrap([]byte("abc"))
+	p := make([]byte, 2)
+	if n, err := b.Read(p); n != 2 || err != nil {
+		t.Fatal(n, err)
+	}
+	if n, err := b.Read(p); n != 1 || err != nil {
+		t.Fatal(n, err)
+	}
+	if n, err := b.Read(p); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+}
+
+type limitedWriter struct {
+	data []byte
+	max  int
+}
+
+// Accepts at most max bytes per call.
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	n := min(len(p), w.max)
+	w.data = append(w.data, p[:n]...)
+	return n, nil
+}
+
+func TestIoCopyTerminates(t *testing.T) {
+	b := Wrap([]byte("hello world"))
+	// Hide WriteTo so io.Copy has to call Read until io.EOF.
+	src := struct{ io.Reader }{b}
+	dst := &limitedWriter{max: 1 << 20}
+	if n, err := io.Copy(dst, src); n != 11 || err != nil || string(dst.data) != "hello world" {
+		t.Fatal(n, err)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	b := Wrap([]byte("abcd"))
+	if err := b.Skip(4); err != nil || b.Position() != 4 {
+		t.Fatal(err, b.Position())
+	}
+	b.Rewind()
+	if err := b.Skip(5); err == nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+	if err := b.Skip(-1); err == nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+}
+
+func TestSliceAliases(t *testing.T) {
+	b := Wrap([]byte("abcdef"))
+	s := b.Slice(2, 5)
+	if s.Position() != 0 || s.Limit() != 3 || s.Capacity() != 3 || string(s.Bytes()) != "cde" {
+		t.Fatal(s.Position(), s.Limit(), s.Capacity())
+	}
+	s.data[0] = 'z'
+	b.data[3] = 'y'
+	if string(b.Bytes()) != "abzyef" || string(s.Bytes()) != "zye" {
+		t.Fatal(string(b.Bytes()), string(s.Bytes()))
+	}
+	if n, err := s.Write([]byte("1234")); n != 3 || err != io.ErrShortWrite || b.data[5] != 'f' {
+		t.Fatal("Slice should not write past its end", n, err)
+	}
+}
+
+func TestSliceBounds(t *testing.T) {
+	b := Wrap([]byte("abcdef"))
+	if s := b.Slice(6, 6); s.Capacity() != 0 {
+		t.Fatal(s.Capacity())
+	}
+	for _, r := range [][2]int{{-1, 2}, {3, 2}, {0, 7}} {
+		if catchPanic(func() { b.Slice(r[0], r[1]) }) == nil {
+			t.Fatal("Slice should panic for", r)
+		}
+	}
+}
+
+func TestReadFull(t *testing.T) {
+	b := Wrap([]byte("abcd"))
+	p := make([]byte, 4)
+	if err := b.ReadFull(p); err != nil || string(p) != "abcd" || b.Remaining() != 0 {
+		t.Fatal(err, p)
+	}
+	if err := b.ReadFull(nil); err != nil {
+		t.Fatal("empty ReadFull should succeed", err)
+	}
+	b.Rewind()
+	b.Skip(1)
+	if err := b.ReadFull(p); err != io.ErrUnexpectedEOF || b.Position() != 1 {
+		t.Fatal(err, b.Position())
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	b := NewBuffer(8)
+	b.Write([]byte("abcd"))
+	if err := b.Truncate(6); err != nil || b.Limit() != 6 {
+		t.Fatal(err, b.Limit())
+	}
+	if err := b.Truncate(3); err != ErrOutOfRange || b.Limit() != 6 {
+		t.Fatal(err, b.Limit())
+	}
+	if err := b.Truncate(9); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+	if n, err := b.Write([]byte("xyz")); n != 2 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+}
+
+func TestGrow(t *testing.T) {
+	b := NewBuffer(4)
+	b.Write([]byte("ab"))
+	b.Grow(8)
+	if b.Capacity() < 10 || b.Limit() != b.Capacity() || b.Position() != 2 {
+		t.Fatal(b.Capacity(), b.Limit(), b.Position())
+	}
+	if n, err := b.Write([]byte("cdefghij")); n != 8 || err != nil {
+		t.Fatal(n, err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != "abcdefghij" {
+		t.Fatal(string(b.Bytes()))
+	}
+}
+
+func TestGrowNoOp(t *testing.T) {
+	b := NewBuffer(8)
+	b.Write([]byte("ab"))
+	data := b.data
+	b.Grow(6)
+	if b.Capacity() != 8 || &b.data[0] != &data[0] || b.Limit() != 8 {
+		t.Fatal("Grow should not resize when there is room", b.Capacity())
+	}
+	if catchPanic(func() { b.Grow(-1) }) == nil {
+		t.Fatal("Grow should panic on negative n")
+	}
+}
+
+func TestGrowReading(t *testing.T) {
+	b := NewBuffer(4)
+	b.Write([]byte("abc"))
+	b.Flip()
+	b.Grow(8)
+	if b.Limit() != 3 || b.Capacity() < 8 || string(b.Bytes()) != "abc" {
+		t.Fatal("Grow should keep the read limit", b.Limit())
+	}
+}
+
+func TestGrowFullReading(t *testing.T) {
+	w := Wrap([]byte{1, 2, 3})
+	w.Grow(10)
+	if w.Remaining() != 3 || w.Limit() != 3 || w.Capacity() < 10 {
+		t.Fatal("Grow exposed bytes past the data", w.Remaining(), w.Capacity())
+	}
+	b := NewBuffer(4)
+	b.WriteUint32(1)
+	b.Flip()
+	b.Grow(8)
+	if b.Remaining() != 4 || b.Capacity() < 8 {
+		t.Fatal(b.Remaining(), b.Capacity())
+	}
+}
+
+func TestGrowAppend(t *testing.T) {
+	a := NewAppendBuffer(8)
+	a.WriteString("abcdef")
+	a.Skip(5)
+	a.Grow(4)
+	if a.Capacity() < 10 || a.Limit() != 6 || string(a.Bytes()) != "f" {
+		t.Fatal("Grow should size from the limit in append mode", a.Capacity(), a.Limit())
+	}
+	data := a.data
+	a.WriteString("ghij")
+	if &a.data[0] != &data[0] || string(a.Bytes()) != "fghij" {
+		t.Fatal("the grown space should fit the write")
+	}
+}
+
+func TestReadUntil(t *testing.T) {
+	b := Wrap([]byte(",ab,c"))
+	if p, err := b.ReadUntil(','); string(p) != "," || err != nil {
+		t.Fatal(string(p), err)
+	}
+	if p, err := b.ReadUntil(','); string(p) != "ab," || err != nil {
+		t.Fatal(string(p), err)
+	}
+	if p, err := b.ReadUntil(','); p != nil || err != io.EOF || b.Position() != 4 {
+		t.Fatal(string(p), err, b.Position())
+	}
+}
+
+func TestReadUntilExclusive(t *testing.T) {
+	b := Wrap([]byte(";x;"))
+	if p, err := b.ReadUntilExclusive(';'); len(p) != 0 || err != nil || b.Position() != 1 {
+		t.Fatal(string(p), err)
+	}
+	if p, err := b.ReadUntilExclusive(';'); string(p) != "x" || err != nil {
+		t.Fatal(string(p), err)
+	}
+	if _, err := b.ReadUntilExclusive('!'); err != io.EOF || b.Position() != 3 {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyFrom(t *testing.T) {
+	src := Wrap([]byte("abcdef"))
+	src.Skip(1)
+	dst := NewBuffer(3)
+	if n, err := dst.CopyFrom(src, 2); n != 2 || err != nil || src.Position() != 3 || dst.Position() != 2 {
+		t.Fatal(n, err, src.Position(), dst.Position())
+	}
+	if n, err := dst.CopyFrom(src, 10); n != 1 || err != io.ErrShortWrite || src.Position() != 4 || dst.Position() != 3 {
+		t.Fatal(n, err, src.Position(), dst.Position())
+	}
+	if n, err := dst.CopyFrom(src, 0); n != 0 || err != nil || src.Position() != 4 {
+		t.Fatal(n, err)
+	}
+	dst.Flip()
+	if string(dst.Bytes()) != "bcd" {
+		t.Fatal(string(dst.Bytes()))
+	}
+	if _, err := dst.CopyFrom(src, -1); err == nil {
+		t.Fatal("negative n should fail")
+	}
+}
+
+func TestNextCopy(t *testing.T) {
+	b := Wrap([]byte("abcd"))
+	alias := b.Next(2)
+	c := b.NextCopy(2)
+	b.data[0], b.data[2] = 'x', 'y'
+	b.Resize(16)
+	b.data[3] = 'z'
+	if string(alias) != "xb" || string(c) != "cd" {
+		t.Fatal(string(alias), string(c))
+	}
+}
+
+func TestCheckpointsNested(t *testing.T) {
+	b := Wrap([]byte("abcdef"))
+	b.Begin()
+	b.Skip(1)
+	b.Begin()
+	b.Skip(2)
+	b.Rollback()
+	if b.Position() != 1 {
+		t.Fatal(b.Position())
+	}
+	b.Begin()
+	b.Skip(3)
+	b.Commit()
+	if b.Position() != 4 {
+		t.Fatal(b.Position())
+	}
+	b.Rollback()
+	if b.Position() != 0 {
+		t.Fatal(b.Position())
+	}
+	if catchPanic(b.Commit) == nil || catchPanic(b.Rollback) == nil {
+		t.Fatal("Commit and Rollback without Begin should panic")
+	}
+}
+
+func TestCheckpointsDiscardedByFlip(t *testing.T) {
+	b := NewBuffer(4)
+	b.Begin()
+	b.WriteUint16(1)
+	b.Flip()
+	if catchPanic(b.Rollback) == nil {
+		t.Fatal("Flip should discard checkpoints")
+	}
+}
+
+func TestWriteToDrains(t *testing.T) {
+	b := Wrap([]byte("hello"))
+	w := &limitedWriter{max: 1}
+	if n, err := b.WriteTo(w); n != 5 || err != nil || string(w.data) != "hello" || b.Remaining() != 0 {
+		t.Fatal(n, err, string(w.data))
+	}
+}
+
+func TestWriteToNoProgress(t *testing.T) {
+	b := Wrap([]byte("hello"))
+	if n, err := b.WriteTo(&limitedWriter{max: 0}); n != 0 || err != io.ErrShortWrite || b.Position() != 0 {
+		t.Fatal(n, err)
+	}
+}
+
+type oneByteReader struct {
+	data []byte
+}
+
+// Returns at most one byte per call.
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	p[0] = r.data[0]
+	r.data = r.data[1:]
+	return 1, nil
+}
+
+type emptyReader struct{}
+
+func (emptyReader) Read(p []byte) (int, error) {
+	return 0, nil
+}
+
+func TestReadFromSlowReader(t *testing.T) {
+	b := NewBuffer(8)
+	if n, err := b.ReadFrom(&oneByteReader{[]byte("hello")}); n != 5 || err != nil {
+		t.Fatal(n, err)
+	}
+	r := &oneByteReader{[]byte("abcdef")}
+	if n, err := b.ReadFrom(r); n != 3 || err != nil || string(r.data) != "def" {
+		t.Fatal("ReadFrom should stop when full", n, err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != "helloabc" {
+		t.Fatal(string(b.Bytes()))
+	}
+}
+
+func TestReadFromGrowable(t *testing.T) {
+	b := NewGrowableBuffer(2)
+	data := make([]byte, 2000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if n, err := b.ReadFrom(&oneByteReader{data}); n != 2000 || err != nil {
+		t.Fatal(n, err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != string(data) {
+		t.Fatal("data mismatch")
+	}
+}
+
+func TestReadFromNoProgress(t *testing.T) {
+	b := NewBuffer(8)
+	if n, err := b.ReadFrom(emptyReader{}); n != 0 || err != io.ErrNoProgress {
+		t.Fatal(n, err)
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	b := NewBufferWithOrder(4, orders[1])
+	b.SetGrowable(true)
+	b.Mark()
+	b.Begin()
+	b.Rewrap([]byte{0, 0, 0, 1, 2})
+	if b.Position() != 0 || b.Limit() != 5 || b.Capacity() != 5 {
+		t.Fatal(b.Position(), b.Limit(), b.Capacity())
+	}
+	if v, err := b.ReadUint32(); err != nil || v != 1 {
+		t.Fatal("byte order not kept", v, err)
+	}
+	if catchPanic(b.Reset) == nil {
+		t.Fatal("mark should be cleared")
+	}
+	if catchPanic(func() { b.Rollback() }) == nil {
+		t.Fatal("checkpoints should be cleared")
+	}
+	if !b.growable {
+		t.Fatal("growable not kept")
+	}
+}
+
+func TestRewrapNoAllocs(t *testing.T) {
+	frames := [][]byte{{1, 0, 0, 0}, {2, 0, 0, 0, 9}, {3, 0, 0, 0}}
+	b := NewBuffer(0)
+	b.Begin()
+	i := 0
+	allocs := testing.AllocsPerRun(1000, func() {
+		b.Rewrap(frames[i%len(frames)])
+		b.Begin()
+		if v, err := b.ReadUint32(); err != nil || int(v) != i%len(frames)+1 {
+			t.Fatal(v, err)
+		}
+		i++
+	})
+	if allocs != 0 {
+		t.Fatal("Rewrap allocated", allocs)
+	}
+}
+
+func TestWriteStringShort(t *testing.T) {
+	b := NewBuffer(4)
+	n, err := b.WriteString("too long")
+	if n != 4 || err != io.ErrShortWrite || b.Position() != 4 {
+		t.Fatal(n, err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != "too " {
+		t.Fatal(string(b.Bytes()))
+	}
+	f := NewBuffer(4)
+	if n, err := f.Write([]byte("too long")); n != 4 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+	if n, err := f.WriteString(""); n != 0 || err != nil {
+		t.Fatal("empty write to a full buffer", n, err)
+	}
+}
+
+func TestWriteStringGrowable(t *testing.T) {
+	b := NewGrowableBuffer(4)
+	if n, err := b.WriteString("not too long"); n != 12 || err != nil {
+		t.Fatal(n, err)
+	}
+	b = NewGrowableBuffer(4)
+	b.SetGrowthPolicy(2, 8)
+	if n, err := b.WriteString("not too long"); n != 8 || err != ErrMaxCapacity {
+		t.Fatal(n, err)
+	}
+}
+
+func TestShrinkToFit(t *testing.T) {
+	b := NewBuffer(64)
+	b.WriteString("hello")
+	b.Flip()
+	b.Skip(1)
+	b.ShrinkToFit()
+	if b.Capacity() != 5 || b.Position() != 1 || b.Limit() != 5 || string(b.Bytes()) != "ello" {
+		t.Fatal(b.Capacity(), b.Position(), b.Limit(), string(b.Bytes()))
+	}
+	data := b.data
+	b.ShrinkToFit()
+	if &data[0] != &b.data[0] {
+		t.Fatal("ShrinkToFit of a tight buffer should not reallocate")
+	}
+
+	e := NewBuffer(8)
+	e.Flip()
+	e.ShrinkToFit()
+	if e.Capacity() != 0 || e.Remaining() != 0 {
+		t.Fatal(e.Capacity())
+	}
+}
+
+func TestShrinkToFitUnaliased(t *testing.T) {
+	data := []byte("abcdef")
+	b := WrapRange(data, 0, 3)
+	b.ShrinkToFit()
+	b.data[0] = 'X'
+	if data[0] != 'a' || string(b.Bytes()) != "Xbc" {
+		t.Fatal("ShrinkToFit should copy to a new backing array")
+	}
+}
+
+func TestRemainingPhases(t *testing.T) {
+	b := NewBuffer(10)
+	if b.WritableRemaining() != 10 || b.Remaining() != 10 {
+		t.Fatal(b.WritableRemaining(), b.Remaining())
+	}
+	b.WriteString("abcd")
+	if b.WritableRemaining() != 6 || b.Remaining() != 6 || !b.HasRemaining() {
+		t.Fatal("write phase", b.WritableRemaining(), b.Remaining())
+	}
+	b.Flip()
+	if b.Remaining() != 4 || b.WritableRemaining() != 10 {
+		t.Fatal("read phase", b.Remaining(), b.WritableRemaining())
+	}
+	b.Skip(3)
+	if b.Remaining() != 1 || b.WritableRemaining() != 7 {
+		t.Fatal(b.Remaining(), b.WritableRemaining())
+	}
+	b.Skip(1)
+	if b.Remaining() != 0 || b.HasRemaining() {
+		t.Fatal(b.Remaining())
+	}
+	b.Clear()
+	b.WriteString("0123456789")
+	if b.WritableRemaining() != 0 || b.Remaining() != 0 {
+		t.Fatal("full", b.WritableRemaining(), b.Remaining())
+	}
+}
+
+func TestReaderIndependent(t *testing.T) {
+	b := Wrap([]byte(`xx{"a":1}yy`))
+	b.SetManual(2, 9)
+	r := b.Reader()
+	data, err := io.ReadAll(r)
+	if err != nil || string(data) != `{"a":1}` || b.Position() != 2 || b.Limit() != 9 {
+		t.Fatal(string(data), err, b.Position())
+	}
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+	if pos, err := r.Seek(1, io.SeekStart); err != nil || pos != 1 {
+		t.Fatal(pos, err)
+	}
+	c := make([]byte, 3)
+	if _, err := io.ReadFull(r, c); err != nil || string(c) != `"a"` || b.Position() != 2 {
+		t.Fatal(string(c), err)
+	}
+	// Reading the Buffer does not move the reader.
+	first, _ := b.ReadByte()
+	r.Seek(0, io.SeekStart)
+	var v map[string]int
+	if err := json.NewDecoder(r).Decode(&v); err != nil || v["a"] != 1 || first != '{' {
+		t.Fatal(v, err)
+	}
+	if b.Position() != 3 {
+		t.Fatal(b.Position())
+	}
+}
+
+func TestAppendAcrossCapacity(t *testing.T) {
+	b := NewAppendBuffer(4)
+	b.WriteString("abc")
+	if string(b.Bytes()) != "abc" {
+		t.Fatal(string(b.Bytes()))
+	}
+	b.WriteUint16(0x6564)
+	b.WriteByte('f')
+	if string(b.Bytes()) != "abcdef" || b.Capacity() < 6 || b.Position() != 0 {
+		t.Fatal(string(b.Bytes()), b.Capacity())
+	}
+	if c, _ := b.ReadByte(); c != 'a' {
+		t.Fatal(c)
+	}
+	if _, err := b.Write(make([]byte, 100)); err != nil || b.Remaining() != 105 || b.Capacity() < 106 {
+		t.Fatal(err, b.Remaining(), b.Capacity())
+	}
+	if string(b.Next(5)) != "bcdef" {
+		t.Fatal("data before growth lost")
+	}
+}
+
+func TestAppendFlipClearCompact(t *testing.T) {
+	b := NewAppendBuffer(8)
+	b.WriteString("abcdef")
+	b.Skip(2)
+	b.Compact()
+	if b.Position() != 0 || string(b.Bytes()) != "cdef" {
+		t.Fatal(b.Position(), string(b.Bytes()))
+	}
+	b.WriteString("gh")
+	if string(b.Bytes()) != "cdefgh" {
+		t.Fatal(string(b.Bytes()))
+	}
+	b.Clear()
+	if b.Position() != 0 || b.Remaining() != 0 {
+		t.Fatal(b.Position(), b.Remaining())
+	}
+	b.WriteString("xyz")
+	b.Skip(1)
+	// Flip discards the unread bytes.
+	b.Flip()
+	if b.Position() != 0 || string(b.Bytes()) != "x" {
+		t.Fatal(b.Position(), string(b.Bytes()))
+	}
+}
+
+func TestSetAppendMode(t *testing.T) {
+	b := NewBuffer(8)
+	b.WriteString("ab")
+	b.SetAppendMode(true)
+	b.WriteString("cd")
+	if string(b.Bytes()) != "abcd" {
+		t.Fatal(string(b.Bytes()))
+	}
+	b.SetAppendMode(false)
+	if b.Position() != 0 || b.Limit() != 4 || b.Mode() != ModeRead {
+		t.Fatal(b.Position(), b.Limit())
+	}
+}
+
+func TestSetAppendModeReading(t *testing.T) {
+	b := Wrap([]byte("abc"))
+	b.SetAppendMode(true)
+	if string(b.Bytes()) != "abc" {
+		t.Fatal(string(b.Bytes()))
+	}
+	r := NewBuffer(8)
+	r.WriteString("abc")
+	r.Flip()
+	r.Skip(1)
+	r.SetAppendMode(true)
+	if r.Position() != 1 || string(r.Bytes()) != "bc" {
+		t.Fatal(r.Position(), string(r.Bytes()))
+	}
+	r.WriteString("d")
+	if string(r.Bytes()) != "bcd" {
+		t.Fatal(string(r.Bytes()))
+	}
+	r.SetAppendMode(false)
+	if r.Mode() != ModeRead || string(r.Bytes()) != "bcd" {
+		t.Fatal(r.Mode(), string(r.Bytes()))
+	}
+	// Back in read mode enabling it again keeps the unread data.
+	r.SetAppendMode(true)
+	r.SetAppendMode(true)
+	if string(r.Bytes()) != "bcd" {
+		t.Fatal(string(r.Bytes()))
+	}
+}
+
+func TestSetAppendModeSwitchBack(t *testing.T) {
+	a := NewAppendBuffer(8)
+	a.WriteString("abc")
+	a.ReadByte()
+	a.SetAppendMode(false)
+	a.SetStrict(true)
+	if c, err := a.ReadByte(); err != nil || c != 'b' {
+		t.Fatal("unread data should be readable after leaving append mode", c, err)
+	}
+	a.Clear()
+	a.WriteString("xy")
+	a.Flip()
+	if string(a.Bytes()) != "xy" {
+		t.Fatal(string(a.Bytes()))
+	}
+}
+
+func TestWritePeekWrite(t *testing.T) {
+	for _, order := range orders {
+		b := NewBufferWithOrder(16, order)
+		b.WriteUint32(7)
+		if got := b.ReadbackRange(0, 4); order.Uint32(got) != 7 || len(got) != 4 || cap(got) != 4 {
+			t.Fatal(got)
+		}
+		pos, lim := b.Snapshot()
+		b.Flip()
+		v, _ := b.ReadUint32()
+		b.Restore(pos, lim)
+		b.WriteUint16(9)
+		if v != 7 || b.Position() != 6 || b.Limit() != 16 {
+			t.Fatal(v, b.Position(), b.Limit())
+		}
+		b.Flip()
+		if a, _ := b.ReadUint32(); a != 7 {
+			t.Fatal(a)
+		}
+		if c, _ := b.ReadUint16(); c != 9 {
+			t.Fatal(c)
+		}
+	}
+}
+
+func TestReadbackRangeBounds(t *testing.T) {
+	b := NewBuffer(8)
+	b.WriteString("abc")
+	for _, r := range [][2]int{{-1, 1}, {2, 1}, {0, 4}} {
+		if catchPanic(func() { b.ReadbackRange(r[0], r[1]) }) == nil {
+			t.Fatal(r)
+		}
+	}
+	if len(b.ReadbackRange(3, 3)) != 0 || b.Position() != 3 {
+		t.Fatal(b.Position())
+	}
+	a := NewAppendBuffer(2)
+	a.WriteString("abcd")
+	a.Skip(1)
+	if string(a.ReadbackRange(0, 4)) != "abcd" || a.Position() != 1 {
+		t.Fatal("append mode readback")
+	}
+}
+
+func TestGrowthPolicyMax(t *testing.T) {
+	b := NewGrowableBuffer(4)
+	b.SetGrowthPolicy(1.5, 10)
+	if n, err := b.Write([]byte("abcdef")); n != 6 || err != nil || b.Capacity() != 6 {
+		t.Fatal(n, err, b.Capacity())
+	}
+	if n, err := b.Write([]byte("ghijkl")); n != 4 || err != ErrMaxCapacity || b.Capacity() != 10 {
+		t.Fatal(n, err, b.Capacity())
+	}
+	if err := b.WriteByte(1); err != ErrMaxCapacity || b.Position() != 10 {
+		t.Fatal(err)
+	}
+	if err := b.WriteUint32(1); err != ErrMaxCapacity {
+		t.Fatal(err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != "abcdefghij" {
+		t.Fatal(string(b.Bytes()))
+	}
+	if catchPanic(func() { NewBuffer(1).SetGrowthPolicy(1, 0) }) == nil {
+		t.Fatal("factor 1 should panic")
+	}
+}
+
+func TestGrowthPolicyReadFrom(t *testing.T) {
+	a := NewAppendBuffer(2)
+	a.SetGrowthPolicy(2, 700)
+	if n, err := a.ReadFrom(strings.NewReader(strings.Repeat("x", 1000))); n != 700 || err != ErrMaxCapacity {
+		t.Fatal(n, err)
+	}
+	if err := a.Insert([]byte("z"), 0); err != ErrMaxCapacity {
+		t.Fatal(err)
+	}
+	g := NewGrowableBuffer(2)
+	g.SetGrowthPolicy(2, 700)
+	if n, err := g.ReadFrom(strings.NewReader(strings.Repeat("x", 600))); n != 600 || err != nil {
+		t.Fatal(n, err)
+	}
+	f := NewBuffer(2)
+	f.SetGrowthPolicy(2, 700)
+	if _, err := f.Write([]byte("abc")); err != io.ErrShortWrite || f.Capacity() != 2 {
+		t.Fatal("a fixed buffer should not grow", err)
+	}
+}
+
+func TestTruncationErrorsWrapErrBufferEmpty(t *testing.T) {
+	reads := map[string]struct {
+		data string
+		read func(*Buffer) error
+	}{
+		"ReadUvarint":         {"\x80\x80", func(b *Buffer) error { _, _, err := b.ReadUvarint(); return err }},
+		"ReadVarBytes":        {"\x05ab", func(b *Buffer) error { _, err := b.ReadVarBytes(); return err }},
+		"ReadVarBytes prefix": {"\x80", func(b *Buffer) error { _, err := b.ReadVarBytes(); return err }},
+		"ReadLString":         {"\x05\x00ab", func(b *Buffer) error { _, err := b.ReadLString(); return err }},
+		"ReadPascalString":    {"\x05ab", func(b *Buffer) error { _, err := b.ReadPascalString(); return err }},
+		"ReadFrame":           {"\x05\x00\x00\x00ab", func(b *Buffer) error { _, err := b.ReadFrame(); return err }},
+		"Read7BitEncodedInt":  {"\x80", func(b *Buffer) error { _, err := b.Read7BitEncodedInt(); return err }},
+		"ReadDotNetString":    {"\x05ab", func(b *Buffer) error { _, err := b.ReadDotNetString(); return err }},
+		"ReadList":            {"\x05\x00\x00\x00ab", func(b *Buffer) error { _, err := ReadList(b, (*Buffer).ReadByte); return err }},
+		"SplitLengthPrefixed": {"\x05ab", func(b *Buffer) error { _, err := b.SplitLengthPrefixed(1); return err }},
+		"SplitFixed":          {"abc", func(b *Buffer) error { _, err := b.SplitFixed(2); return err }},
+		"ReadBits":            {"a", func(b *Buffer) error { _, err := NewBitReader(b, MSBFirst).ReadBits(9); return err }},
+		"ReadUint32":          {"ab", func(b *Buffer) error { _, err := b.ReadUint32(); return err }},
+	}
+	for name, r := range reads {
+		b := Wrap([]byte(r.data))
+		if err := r.read(b); !errors.Is(err, ErrBufferEmpty) || b.Position() != 0 {
+			t.Fatal(name, err, b.Position())
+		}
+	}
+}
+
+func TestConcatEmpty(t *testing.T) {
+	e := Concat()
+	if e.Remaining() != 0 || e.Position() != 0 || e.order == nil {
+		t.Fatal(e.Remaining())
+	}
+	z := Concat(Wrap(nil), NewBuffer(0))
+	if z.Remaining() != 0 {
+		t.Fatal(z.Remaining())
+	}
+}
+
+func TestConcatSingle(t *testing.T) {
+	a := NewBufferWithOrder(3, orders[1])
+	a.WriteString("xab")
+	a.Flip()
+	a.Skip(1)
+	one := Concat(a)
+	if string(one.Bytes()) != "ab" || one.order != orders[1] || a.Position() != 1 {
+		t.Fatal(string(one.Bytes()))
+	}
+	one.data[0] = 'z'
+	if a.data[1] != 'a' {
+		t.Fatal("Concat should copy")
+	}
+}
+
+func TestConcatMany(t *testing.T) {
+	a := Wrap([]byte("xab"))
+	a.Skip(1)
+	c := Concat(a, Wrap(nil), Wrap([]byte("cd")), WrapRange([]byte("efg"), 0, 1))
+	if string(c.Bytes()) != "abcde" || c.Capacity() != 5 || a.Position() != 1 {
+		t.Fatal(string(c.Bytes()))
+	}
+}
+
+var _ io.StringWriter = (*Buffer)(nil)
+
+func TestWriteStringNoAllocs(t *testing.T) {
+	b := NewBuffer(64)
+	s := strings.Repeat("x", 40)
+	allocs := testing.AllocsPerRun(100, func() {
+		b.Clear()
+		io.WriteString(b, s)
+	})
+	if allocs != 0 {
+		t.Fatal("io.WriteString allocated", allocs)
+	}
+}
+
+func BenchmarkWriteString(b *testing.B) {
+	buf := NewBuffer(64)
+	s := strings.Repeat("x", 40)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Clear()
+		buf.WriteString(s)
+	}
+}
+
+// Keeps the converted slice on the heap, like a []byte(s) passed through an io.Writer.
+var convertedSink []byte
+
+func BenchmarkWriteStringConverted(b *testing.B) {
+	buf := NewBuffer(64)
+	s := strings.Repeat("x", 40)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Clear()
+		convertedSink = []byte(s)
+		buf.Write(convertedSink)
+	}
+}
+
+func TestBytesCopySurvivesResize(t *testing.T) {
+	data := []byte("xabc")
+	b := Wrap(data)
+	b.Skip(1)
+	c := b.BytesCopy()
+	aliased := b.Bytes()
+	b.Resize(10)
+	b.data[1] = 'z'
+	data[2] = 'y'
+	if string(c) != "abc" || b.Position() != 1 {
+		t.Fatal(string(c))
+	}
+	if string(aliased) != "ayc" {
+		t.Fatal("Bytes should alias the old backing array", string(aliased))
+	}
+	if c := Wrap(nil).BytesCopy(); len(c) != 0 {
+		t.Fatal(c)
+	}
+}
+
+func TestLimitReaderPartial(t *testing.T) {
+	b := Wrap([]byte("abcdefgh"))
+	b.Skip(1)
+	r := b.LimitReader(4)
+	p := make([]byte, 3)
+	if n, err := r.Read(p); n != 3 || err != nil || string(p) != "bcd" || b.Position() != 4 {
+		t.Fatal(n, err, b.Position())
+	}
+	// Reads from the buffer in between are not counted by the reader.
+	if c, _ := b.ReadByte(); c != 'e' {
+		t.Fatal(c)
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil || string(rest) != "f" || b.Position() != 6 {
+		t.Fatal(string(rest), err, b.Position())
+	}
+	if n, err := r.Read(p); n != 0 || err != io.EOF || b.Position() != 6 {
+		t.Fatal(n, err)
+	}
+	all, _ := io.ReadAll(b.LimitReader(100))
+	if string(all) != "gh" || b.Position() != 8 {
+		t.Fatal(string(all))
+	}
+	if n, err := b.LimitReader(0).Read(p); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+	if catchPanic(func() { b.LimitReader(-1) }) == nil {
+		t.Fatal("negative n should panic")
+	}
+}
+
+func TestReadAtomicRewinds(t *testing.T) {
+	b := Wrap([]byte{1, 0, 2, 0, 0})
+	b.Mark()
+	var x, y uint16
+	err := b.ReadAtomic(func(b *Buffer) error {
+		var err error
+		if x, err = b.ReadUint16(); err != nil {
+			return err
+		}
+		if y, err = b.ReadUint16(); err != nil {
+			return err
+		}
+		_, err = b.ReadUint32()
+		return err
+	})
+	if !errors.Is(err, ErrBufferEmpty) || b.Position() != 0 || x != 1 || y != 2 {
+		t.Fatal(err, b.Position())
+	}
+	if b.UnreadByte() == nil {
+		t.Fatal("UnreadByte after a rewind should fail")
+	}
+	b.Reset()
+	err = b.ReadAtomic(func(b *Buffer) error {
+		_, err := b.ReadUint32()
+		return err
+	})
+	if err != nil || b.Position() != 4 {
+		t.Fatal(err, b.Position())
+	}
+}
+
+func TestReadAtomicCustomError(t *testing.T) {
+	b := Wrap([]byte("abcd"))
+	b.Skip(1)
+	fail := errors.New("bad record")
+	err := b.ReadAtomic(func(b *Buffer) error {
+		b.ReadByte()
+		b.Mark()
+		b.ReadByte()
+		return fail
+	})
+	if err != fail || b.Position() != 1 {
+		t.Fatal(err, b.Position())
+	}
+	if catchPanic(b.Reset) == nil {
+		t.Fatal("mark set inside ReadAtomic should be discarded")
+	}
+	// The limit moved below the saved position.
+	err = b.ReadAtomic(func(b *Buffer) error {
+		b.Skip(2)
+		b.Truncate(b.Position())
+		b.SetManual(0, 0)
+		return fail
+	})
+	if err != fail || b.Position() != 0 || b.Limit() != 0 {
+		t.Fatal(err, b.Position(), b.Limit())
+	}
+}
+
+func TestWriterWriteMode(t *testing.T) {
+	g := NewGrowableBuffer(8)
+	fmt.Fprintf(g.Writer(), "hi")
+	g.Flip()
+	if string(g.Bytes()) != "hi" {
+		t.Fatal(string(g.Bytes()))
+	}
+	g.Clear()
+	fmt.Fprintf(g.Writer(), "hello %s", "world")
+	if g.Position() != 11 || g.Capacity() < 11 {
+		t.Fatal(g.Position(), g.Capacity())
+	}
+	g.Flip()
+	if string(g.Bytes()) != "hello world" {
+		t.Fatal(string(g.Bytes()))
+	}
+	f := NewBuffer(8)
+	json.NewEncoder(f.Writer()).Encode(1)
+	f.Flip()
+	if string(f.Bytes()) != "1\n" {
+		t.Fatal(string(f.Bytes()))
+	}
+	f.Clear()
+	if n, err := f.Writer().Write([]byte("too long!")); n != 8 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+}
+
+func TestWriterReadMode(t *testing.T) {
+	b := NewBuffer(8)
+	b.WriteString("ab")
+	b.Flip()
+	b.ReadByte()
+	fmt.Fprintf(b.Writer(), "%d", 123)
+	if string(b.Bytes()) != "b123" || b.Position() != 1 {
+		t.Fatal(string(b.Bytes()))
+	}
+	if n, err := b.Writer().Write([]byte("xxxxxx")); n != 3 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+	g := NewGrowableBuffer(1)
+	g.Flip()
+	fmt.Fprintf(g.Writer(), "hello %s", "world")
+	if string(g.Bytes()) != "hello world" || g.Position() != 0 {
+		t.Fatal(string(g.Bytes()))
+	}
+	w := Wrap([]byte("ab"))
+	if n, err := w.Writer().Write([]byte("c")); n != 0 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+}
+
+func TestWriterAppendMode(t *testing.T) {
+	a := NewAppendBuffer(1)
+	fmt.Fprintf(a.Writer(), "hi")
+	a.ReadByte()
+	fmt.Fprintf(a.Writer(), " there")
+	if string(a.Bytes()) != "i there" {
+		t.Fatal(string(a.Bytes()))
+	}
+}
+
+func TestCapacityQuantities(t *testing.T) {
+	b := NewBuffer(10)
+	b.WriteUint16(1)
+	b.SetManual(2, 6)
+	if b.Capacity() != 10 || b.Length() != 4 || b.Remaining() != 4 {
+		t.Fatal(b.Capacity(), b.Length(), b.Remaining())
+	}
+	if b.CapacityRemaining() != 8 || b.WritableRemaining() != 8 {
+		t.Fatal(b.CapacityRemaining(), b.WritableRemaining())
+	}
+	b.SetManual(6, 6)
+	if b.Length() != 0 || b.CapacityRemaining() != 4 {
+		t.Fatal(b.Length(), b.CapacityRemaining())
+	}
+	g := NewGrowableBuffer(2)
+	g.WriteString("abcde")
+	if g.Capacity() < 5 || g.CapacityRemaining() != g.Capacity()-5 || g.Length() != g.Limit()-5 {
+		t.Fatal(g.Capacity(), g.CapacityRemaining(), g.Length())
+	}
+}
+
+func TestReadTee(t *testing.T) {
+	b := NewBuffer(32)
+	b.Write([]byte("ab"))
+	b.WriteByte('c')
+	b.WriteUint32(0x64636261)
+	b.WriteCString("xy")
+	b.WriteString("é")
+	b.WriteUint16(0x7a7a)
+	b.Flip()
+	var tee strings.Builder
+	b.SetReadTee(&tee)
+	b.PeekByte()
+	b.PeekBytes(4)
+	if tee.Len() != 0 {
+		t.Fatal("peeking should not write to the tee", tee.String())
+	}
+	b.Read(make([]byte, 2))
+	b.ReadByte()
+	b.ReadUint32()
+	b.ReadCString()
+	b.ReadRune()
+	b.PeekUint16()
+	if tee.String() != "abcabcdxy\x00é" {
+		t.Fatalf("%q", tee.String())
+	}
+	b.SetReadTee(nil)
+	if v, err := b.ReadUint16(); err != nil || v != 0x7a7a || tee.String() != "abcabcdxy\x00é" {
+		t.Fatalf("%x %v %q", v, err, tee.String())
+	}
+}
+
+func TestPeekAtLimit(t *testing.T) {
+	b := Wrap([]byte("abc"))
+	if p, err := b.PeekBytes(5); err != nil || string(p) != "abc" || b.Position() != 0 {
+		t.Fatal(string(p), err, b.Position())
+	}
+	b.Skip(2)
+	if v, err := b.PeekUint16(); !errors.Is(err, ErrBufferEmpty) || v != 0 || b.Position() != 2 {
+		t.Fatal(v, err, b.Position())
+	}
+	if p, err := b.PeekBytes(2); err != nil || string(p) != "c" || b.Position() != 2 {
+		t.Fatal(string(p), err, b.Position())
+	}
+	b.Skip(1)
+	if c, err := b.PeekByte(); err != ErrBufferEmpty || c != 0 || b.Position() != 3 {
+		t.Fatal(c, err, b.Position())
+	}
+	if p, err := b.PeekBytes(1); err != ErrBufferEmpty || p != nil || b.Position() != 3 {
+		t.Fatal(p, err, b.Position())
+	}
+	if p, err := b.PeekBytes(0); err != ErrBufferEmpty || b.Position() != 3 {
+		t.Fatal(p, err, b.Position())
+	}
+}
+
+func TestSeek(t *testing.T) {
+	for _, c := range []struct {
+		offset   int64
+		whence   int
+		position int64
+		err      bool
+	}{
+		{0, io.SeekStart, 0, false},
+		{3, io.SeekStart, 3, false},
+		{6, io.SeekStart, 6, false},
+		{-1, io.SeekStart, 2, true},
+		{7, io.SeekStart, 2, true},
+		{2, io.SeekCurrent, 4, false},
+		{-2, io.SeekCurrent, 0, false},
+		{-3, io.SeekCurrent, 2, true},
+		{5, io.SeekCurrent, 2, true},
+		{0, io.SeekEnd, 6, false},
+		{-6, io.SeekEnd, 0, false},
+		{-7, io.SeekEnd, 2, true},
+		{1, io.SeekEnd, 2, true},
+		{0, 3, 2, true},
+	} {
+		// The limit is below the capacity to check that it bounds the result.
+		b := NewBuffer(8)
+		b.WriteString("abcdef")
+		b.Flip()
+		b.Skip(2)
+		abs, err := b.Seek(c.offset, c.whence)
+		if (err != nil) != c.err || (err == nil && abs != c.position) || int64(b.Position()) != c.position {
+			t.Fatal(c, abs, err, b.Position())
+		}
+	}
+}
+
+func TestDiscardPartial(t *testing.T) {
+	b := Wrap([]byte("abc"))
+	if n, err := b.Discard(2); n != 2 || err != nil {
+		t.Fatal(n, err)
+	}
+	if n, err := b.Discard(5); n != 1 || err != io.EOF || b.Remaining() != 0 {
+		t.Fatal(n, err, b.Remaining())
+	}
+	if n, err := b.Discard(1); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+}
+
+func TestAlign(t *testing.T) {
+	b := NewBuffer(8)
+	if n, err := b.Align(4); n != 0 || err != nil || b.Position() != 0 {
+		t.Fatal(n, err, b.Position())
+	}
+	b.WriteByte(1)
+	if n, err := b.Align(4); n != 3 || err != nil || b.Position() != 4 {
+		t.Fatal(n, err, b.Position())
+	}
+	if n, err := b.Align(4); n != 0 || err != nil || b.Position() != 4 {
+		t.Fatal(n, err, b.Position())
+	}
+	b.data[5], b.data[6], b.data[7] = 9, 9, 9
+	b.WriteByte(2)
+	// The padding to 16 does not fit, the remaining space is zeroed like Fill.
+	if n, err := b.Align(16); n != 3 || err != io.ErrShortWrite || b.Position() != 8 {
+		t.Fatal(n, err, b.Position())
+	}
+	if string(b.data) != "\x01\x00\x00\x00\x02\x00\x00\x00" {
+		t.Fatalf("% x", b.data)
+	}
+	if _, err := b.Align(0); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestAlignRead(t *testing.T) {
+	b := NewBuffer(8)
+	b.WriteString("abcdef")
+	b.Flip()
+	if err := b.AlignRead(4); err != nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+	b.Skip(1)
+	if err := b.AlignRead(4); err != nil || b.Position() != 4 {
+		t.Fatal(err, b.Position())
+	}
+	b.Skip(1)
+	// The boundary at 8 is past the limit at 6.
+	if err := b.AlignRead(4); err == nil || b.Position() != 5 {
+		t.Fatal(err, b.Position())
+	}
+	if err := b.AlignRead(-1); err == nil || b.Position() != 5 {
+		t.Fatal(err, b.Position())
+	}
+}
+
+func TestFillGrowable(t *testing.T) {
+	b := NewBuffer(2)
+	b.SetGrowable(true)
+	b.WriteByte(1)
+	if n, err := b.Fill(7, 5); n != 5 || err != nil || b.Position() != 6 || b.Capacity() < 6 {
+		t.Fatal(n, err, b.Position(), b.Capacity())
+	}
+	b.Flip()
+	if string(b.Bytes()) != "\x01\x07\x07\x07\x07\x07" {
+		t.Fatalf("% x", b.Bytes())
+	}
+
+	b = NewBuffer(2)
+	b.SetGrowable(true)
+	b.SetGrowthPolicy(2, 8)
+	b.WriteByte(1)
+	// Grows to the max capacity and truncates the rest.
+	if n, err := b.Fill(7, 10); n != 7 || err != ErrMaxCapacity || b.Position() != 8 || b.Capacity() != 8 {
+		t.Fatal(n, err, b.Position(), b.Capacity())
+	}
+}
+
+func TestTrySetManual(t *testing.T) {
+	b := NewBuffer(8)
+	b.SetManual(2, 6)
+	for _, c := range [][2]int{{-1, 4}, {5, 4}, {0, 9}, {9, 9}} {
+		if err := b.TrySetManual(c[0], c[1]); err != ErrOutOfRange || b.Position() != 2 || b.Limit() != 6 {
+			t.Fatal(c, err, b.Position(), b.Limit())
+		}
+	}
+	if err := b.TrySetManual(8, 8); err != nil || b.Position() != 8 || b.Limit() != 8 {
+		t.Fatal(err, b.Position(), b.Limit())
+	}
+}
+
+func TestTryChangePosition(t *testing.T) {
+	b := NewBuffer(8)
+	b.SetManual(2, 6)
+	for _, n := range []int{-3, 5, 100} {
+		if err := b.TryChangePosition(n); err != ErrOutOfRange || b.Position() != 2 || b.Limit() != 6 {
+			t.Fatal(n, err, b.Position(), b.Limit())
+		}
+	}
+	if err := b.TryChangePosition(4); err != nil || b.Position() != 6 {
+		t.Fatal(err, b.Position())
+	}
+	if err := b.TryChangePosition(-6); err != nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+}
@@ -0,0 +1,85 @@
+package altdata
+
+import "errors"
+import "fmt"
+import "hash/crc32"
+import "math"
+
+// Returns the IEEE CRC32 of the absolute range [start:end] of the backing array.
+// DOES NOT move the position. Will panic if start > end or end > capacity!
+func (b *Buffer) CRC32(start, end int) uint32 {
+	if start < 0 || start > end || end > cap(b.data) {
+		panic("Buffer range out of range!")
+	}
+	return crc32.ChecksumIEEE(b.data[start:end])
+}
+
+// Writes the CRC32 of [start:position] using the byte order.
+// In append mode the range is [start:limit].
+func (b *Buffer) AppendCRC32(start int) error {
+	end := b.writePosition()
+	if start < 0 || start > end {
+		return ErrOutOfRange
+	}
+	return b.WriteUint32(b.CRC32(start, end))
+}
+
+// Reads a CRC32 using the byte order and reports whether it matches
+// the CRC32 of [start:position] before the read.
+func (b *Buffer) VerifyCRC32(start int) (bool, error) {
+	if start < 0 || start > b.position {
+		return false, ErrOutOfRange
+	}
+	sum := b.CRC32(start, b.position)
+	v, err := b.ReadUint32()
+	if err != nil {
+		return false, err
+	}
+	return v == sum, nil
+}
+
+// Set the checksum used by WriteFrame and ReadFrame, nil means crc32.ChecksumIEEE.
+func (b *Buffer) SetFrameChecksum(checksum func([]byte) uint32) {
+	b.checksum = checksum
+}
+
+func (b *Buffer) frameChecksum(p []byte) uint32 {
+	if b.checksum == nil {
+		return crc32.ChecksumIEEE(p)
+	}
+	return b.checksum(p)
+}
+
+// Writes a frame of a uint32 length, the payload and a uint32 checksum of the payload,
+// using the byte order. Returns an error and does not move if the frame does not fit.
+func (b *Buffer) WriteFrame(payload []byte) error {
+	if uint64(len(payload)) > math.MaxUint32 {
+		return errors.New("Payload too large for frame")
+	}
+	p, err := b.reserve(len(payload) + 8)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint32(p, uint32(len(payload)))
+	copy(p[4:], payload)
+	b.order.PutUint32(p[4+len(payload):], b.frameChecksum(payload))
+	return nil
+}
+
+// Reads a frame written by WriteFrame and returns the payload, sharing the backing array.
+// Returns an error and does not move if the frame is truncated or the checksum does not match.
+func (b *Buffer) ReadFrame() ([]byte, error) {
+	n, err := b.PeekUint32()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(n)+8 > uint64(b.limit-b.position) {
+		return nil, fmt.Errorf("Frame truncated by end of buffer: %w", ErrBufferEmpty)
+	}
+	p := b.data[b.position+4 : b.position+4+int(n)]
+	if b.order.Uint32(b.data[b.position+4+int(n):]) != b.frameChecksum(p) {
+		return nil, errors.New("Frame checksum mismatch")
+	}
+	b.Next(int(n) + 8)
+	return p, nil
+}
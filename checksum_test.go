@@ -0,0 +1,99 @@
+package altdata
+
+import "hash/adler32"
+import "testing"
+
+func TestFrameRoundTrip(t *testing.T) {
+	for _, order := range orders {
+		b := NewBufferWithOrder(64, order)
+		b.WriteFrame([]byte("first"))
+		b.WriteFrame(nil)
+		b.Flip()
+		if p, err := b.ReadFrame(); err != nil || string(p) != "first" {
+			t.Fatal(string(p), err)
+		}
+		if p, err := b.ReadFrame(); err != nil || len(p) != 0 || b.Remaining() != 0 {
+			t.Fatal(p, err)
+		}
+	}
+}
+
+func TestFrameCorrupted(t *testing.T) {
+	b := NewBuffer(64)
+	b.WriteFrame([]byte("payload"))
+	b.Flip()
+	for i := 4; i < b.Limit(); i++ {
+		b.data[i] ^= 0x10
+		if _, err := b.ReadFrame(); err == nil || b.Position() != 0 {
+			t.Fatal(i, err)
+		}
+		b.data[i] ^= 0x10
+	}
+	if p, err := b.ReadFrame(); err != nil || string(p) != "payload" {
+		t.Fatal(string(p), err)
+	}
+}
+
+func TestFrameTruncated(t *testing.T) {
+	b := NewBuffer(64)
+	b.WriteFrame([]byte("payload"))
+	b.Flip()
+	for n := 0; n < b.Limit(); n++ {
+		w := Wrap(b.data[:n])
+		if _, err := w.ReadFrame(); err == nil || w.Position() != 0 {
+			t.Fatal(n, err)
+		}
+	}
+	// A huge declared length must not overflow the bounds check.
+	h := Wrap([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+	if _, err := h.ReadFrame(); err == nil || h.Position() != 0 {
+		t.Fatal(err)
+	}
+}
+
+func TestFrameWriteFull(t *testing.T) {
+	b := NewBuffer(10)
+	if err := b.WriteFrame([]byte("abc")); err == nil || b.Position() != 0 {
+		t.Fatal(err)
+	}
+}
+
+func TestFrameChecksum(t *testing.T) {
+	b := NewBuffer(64)
+	b.SetFrameChecksum(adler32.Checksum)
+	b.WriteFrame([]byte("payload"))
+	b.Flip()
+	if b.order.Uint32(b.data[11:]) != adler32.Checksum([]byte("payload")) {
+		t.Fatal("custom checksum not used")
+	}
+	b.SetFrameChecksum(nil)
+	if _, err := b.ReadFrame(); err == nil {
+		t.Fatal("CRC32 should not match an adler32 frame")
+	}
+}
+
+func TestCRC32(t *testing.T) {
+	b := NewBuffer(16)
+	b.WriteString("xhello")
+	if err := b.AppendCRC32(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AppendCRC32(20); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+	b.Flip()
+	b.Skip(6)
+	if ok, err := b.VerifyCRC32(1); err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+	b.SetManual(6, 10)
+	if ok, err := b.VerifyCRC32(0); err != nil || ok {
+		t.Fatal("checksum of a different range should not match", ok, err)
+	}
+	if _, err := b.VerifyCRC32(11); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+	if catchPanic(func() { b.CRC32(2, 1) }) == nil {
+		t.Fatal("CRC32 with start > end should panic")
+	}
+}
@@ -0,0 +1,21 @@
+package altdata
+
+import "bytes"
+
+// Reports whether the remaining bytes of b and other are equal.
+// DOES NOT move the position of either buffer.
+func (b *Buffer) Equal(other *Buffer) bool {
+	return bytes.Equal(b.Bytes(), other.Bytes())
+}
+
+// Reports whether the remaining bytes of b are equal to p.
+// DOES NOT move the position.
+func (b *Buffer) EqualBytes(p []byte) bool {
+	return bytes.Equal(b.Bytes(), p)
+}
+
+// Compares the remaining bytes of b and other lexicographically like bytes.Compare,
+// returning -1, 0 or 1. DOES NOT move the position of either buffer.
+func (b *Buffer) Compare(other *Buffer) int {
+	return bytes.Compare(b.Bytes(), other.Bytes())
+}
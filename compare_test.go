@@ -0,0 +1,40 @@
+package altdata
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		a, b string
+		r    int
+	}{{"", "", 0}, {"", "a", -1}, {"a", "", 1}, {"ab", "abc", -1}, {"abc", "abc", 0},
+		{"b", "abc", 1}, {"\x00", "", 1}, {"\xff", "\x00\xff", 1}}
+	for _, c := range cases {
+		a, b := Wrap([]byte(c.a)), Wrap([]byte(c.b))
+		if r := a.Compare(b); r != c.r {
+			t.Fatal(c, r)
+		}
+		if r := b.Compare(a); r != -c.r {
+			t.Fatal(c, "reversed", r)
+		}
+		if a.Equal(b) != (c.r == 0) || a.EqualBytes([]byte(c.b)) != (c.r == 0) {
+			t.Fatal(c, "equal")
+		}
+	}
+}
+
+func TestCompareRemaining(t *testing.T) {
+	a := Wrap([]byte("xxabcyy"))
+	a.SetManual(2, 5)
+	b := Wrap([]byte("abc"))
+	if !a.Equal(b) || a.Compare(b) != 0 || !a.EqualBytes([]byte("abc")) || a.Position() != 2 {
+		t.Fatal("only the remaining bytes should be compared")
+	}
+	if !a.Equal(a) || a.Compare(a) != 0 {
+		t.Fatal("self compare")
+	}
+	e := Wrap([]byte("abc"))
+	e.Skip(3)
+	if !e.Equal(Wrap(nil)) || !e.EqualBytes(nil) || e.Compare(NewBuffer(0)) != 0 {
+		t.Fatal("drained buffer should equal an empty buffer")
+	}
+}
@@ -0,0 +1,78 @@
+package altdata
+
+import "bytes"
+import "compress/gzip"
+import "compress/zlib"
+import "io"
+
+// Returns a new buffer, ready for reading, with the remaining bytes gzip compressed
+// using the default compression level. DOES NOT move the position.
+func (b *Buffer) CompressGzip() (*Buffer, error) {
+	return b.CompressGzipLevel(gzip.DefaultCompression)
+}
+
+// Same as CompressGzip with a compression level from compress/gzip.
+func (b *Buffer) CompressGzipLevel(level int) (*Buffer, error) {
+	return b.compress(func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriterLevel(w, level)
+	})
+}
+
+// Returns a new buffer, ready for reading, with the remaining bytes gzip decompressed.
+// DOES NOT move the position.
+func (b *Buffer) DecompressGzip() (*Buffer, error) {
+	return b.decompress(func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	})
+}
+
+// Returns a new buffer, ready for reading, with the remaining bytes zlib compressed
+// using the default compression level. DOES NOT move the position.
+func (b *Buffer) CompressZlib() (*Buffer, error) {
+	return b.CompressZlibLevel(zlib.DefaultCompression)
+}
+
+// Same as CompressZlib with a compression level from compress/zlib.
+func (b *Buffer) CompressZlibLevel(level int) (*Buffer, error) {
+	return b.compress(func(w io.Writer) (io.WriteCloser, error) {
+		return zlib.NewWriterLevel(w, level)
+	})
+}
+
+// Returns a new buffer, ready for reading, with the remaining bytes zlib decompressed.
+// DOES NOT move the position.
+func (b *Buffer) DecompressZlib() (*Buffer, error) {
+	return b.decompress(zlib.NewReader)
+}
+
+func (b *Buffer) compress(newWriter func(io.Writer) (io.WriteCloser, error)) (*Buffer, error) {
+	out := NewGrowableBuffer(b.Length()/2 + 64)
+	w, err := newWriter(out)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(b.Bytes()); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	out.Flip()
+	out.SetByteOrder(b.order)
+	return out, nil
+}
+
+func (b *Buffer) decompress(newReader func(io.Reader) (io.ReadCloser, error)) (*Buffer, error) {
+	r, err := newReader(bytes.NewReader(b.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	out := NewGrowableBuffer(b.Length()*2 + 64)
+	if _, err = out.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	out.Flip()
+	out.SetByteOrder(b.order)
+	return out, nil
+}
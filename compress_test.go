@@ -0,0 +1,78 @@
+package altdata
+
+import "bytes"
+import "compress/gzip"
+import "math/rand"
+import "testing"
+
+func compressData() map[string][]byte {
+	random := make([]byte, 64*1024)
+	rand.New(rand.NewSource(1)).Read(random)
+	return map[string][]byte{
+		"empty":          {},
+		"compressible":   bytes.Repeat([]byte("altdata buffer "), 4096),
+		"incompressible": random,
+	}
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	type codec struct {
+		compress   func(*Buffer) (*Buffer, error)
+		decompress func(*Buffer) (*Buffer, error)
+	}
+	codecs := map[string]codec{
+		"gzip":      {(*Buffer).CompressGzip, (*Buffer).DecompressGzip},
+		"zlib":      {(*Buffer).CompressZlib, (*Buffer).DecompressZlib},
+		"gzip best": {func(b *Buffer) (*Buffer, error) { return b.CompressGzipLevel(gzip.BestCompression) }, (*Buffer).DecompressGzip},
+		"zlib none": {func(b *Buffer) (*Buffer, error) { return b.CompressZlibLevel(gzip.NoCompression) }, (*Buffer).DecompressZlib},
+	}
+	for cname, c := range codecs {
+		for dname, data := range compressData() {
+			b := Wrap(append([]byte("skip"), data...))
+			b.Skip(4)
+			z, err := c.compress(b)
+			if err != nil {
+				t.Fatal(cname, dname, err)
+			}
+			if b.Position() != 4 {
+				t.Fatal(cname, dname, "compress moved the position")
+			}
+			if dname == "compressible" && cname != "zlib none" && z.Length() >= len(data)/10 {
+				t.Fatal(cname, dname, "compressed to", z.Length())
+			}
+			out, err := c.decompress(z)
+			if err != nil {
+				t.Fatal(cname, dname, err)
+			}
+			if z.Position() != 0 || !bytes.Equal(out.Bytes(), data) {
+				t.Fatal(cname, dname, "round trip failed", out.Length())
+			}
+		}
+	}
+}
+
+func TestCompressByteOrder(t *testing.T) {
+	b := NewBufferWithOrder(4, orders[1])
+	b.WriteUint32(0x01020304)
+	b.Flip()
+	z, _ := b.CompressZlib()
+	out, _ := z.DecompressZlib()
+	if v, err := out.ReadUint32(); err != nil || v != 0x01020304 {
+		t.Fatal(v, err)
+	}
+}
+
+func TestDecompressInvalid(t *testing.T) {
+	b := Wrap([]byte("not compressed data"))
+	if _, err := b.DecompressGzip(); err == nil {
+		t.Fatal("DecompressGzip should fail")
+	}
+	if _, err := b.DecompressZlib(); err == nil {
+		t.Fatal("DecompressZlib should fail")
+	}
+	z, _ := Wrap(bytes.Repeat([]byte("x"), 1000)).CompressGzip()
+	z.Truncate(z.Length() / 2)
+	if _, err := z.DecompressGzip(); err == nil {
+		t.Fatal("DecompressGzip of a truncated stream should fail")
+	}
+}
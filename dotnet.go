@@ -0,0 +1,93 @@
+package altdata
+
+import "errors"
+import "fmt"
+import "math"
+
+// Writes v in the 7-bit encoded format of .NET BinaryWriter.Write7BitEncodedInt.
+// v must fit in an int32, negative values are written as 5 bytes like in .NET.
+func (b *Buffer) Write7BitEncodedInt(v int) error {
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return errors.New("Value does not fit in a 7-bit encoded int")
+	}
+	var tmp [5]byte
+	n := 0
+	for u := uint32(v); ; u >>= 7 {
+		if u < 0x80 {
+			tmp[n] = byte(u)
+			n++
+			break
+		}
+		tmp[n] = byte(u) | 0x80
+		n++
+	}
+	p, err := b.reserve(n)
+	if err != nil {
+		return err
+	}
+	copy(p, tmp[:n])
+	return nil
+}
+
+// Reads an int in the 7-bit encoded format of .NET BinaryReader.Read7BitEncodedInt.
+// Returns an error and does not move if the int is truncated, longer than 5 bytes,
+// overflows 32 bits, or is overlong with trailing zero groups.
+func (b *Buffer) Read7BitEncodedInt() (int, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, err
+	}
+	var u uint32
+	for i := 0; i < 5; i++ {
+		if b.position+i >= b.limit {
+			return 0, fmt.Errorf("7-bit encoded int truncated by end of buffer: %w", ErrBufferEmpty)
+		}
+		c := b.data[b.position+i]
+		if i == 4 && c > 0x0f {
+			return 0, errors.New("7-bit encoded int overflows 32 bits")
+		}
+		u |= uint32(c&0x7f) << (7 * i)
+		if c < 0x80 {
+			if c == 0 && i > 0 {
+				return 0, errors.New("Overlong 7-bit encoded int")
+			}
+			b.position += i + 1
+			b.tee(b.data[b.position-i-1 : b.position])
+			return int(int32(u)), nil
+		}
+	}
+	return 0, errors.New("7-bit encoded int overflows 32 bits")
+}
+
+// Writes s like .NET BinaryWriter.Write(string), as UTF-8 prefixed with
+// its length in bytes as a 7-bit encoded int.
+// Returns an error and does not move the position if it does not fit.
+func (b *Buffer) WriteDotNetString(s string) error {
+	start := b.writePosition()
+	err := b.Write7BitEncodedInt(len(s))
+	if err == nil {
+		var p []byte
+		if p, err = b.reserve(len(s)); err == nil {
+			copy(p, s)
+		}
+	}
+	if err != nil {
+		b.setWritePosition(start)
+	}
+	return err
+}
+
+// Reads a string written by .NET BinaryWriter.Write(string).
+// Returns an error and does not move the position if the declared length is
+// negative or exceeds the remaining bytes.
+func (b *Buffer) ReadDotNetString() (string, error) {
+	start := b.position
+	n, err := b.Read7BitEncodedInt()
+	if err != nil {
+		return "", err
+	}
+	if n < 0 || n > b.limit-b.position {
+		b.position = start
+		return "", fmt.Errorf("Length exceeds remaining bytes in buffer: %w", ErrBufferEmpty)
+	}
+	return string(b.Next(n)), nil
+}
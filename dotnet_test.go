@@ -0,0 +1,87 @@
+package altdata
+
+import "math"
+import "testing"
+
+// Bytes written by .NET BinaryWriter.Write7BitEncodedInt and Write(string).
+var dotNetGolden = []struct {
+	v    int
+	data string
+}{
+	{0, "\x00"},
+	{1, "\x01"},
+	{127, "\x7f"},
+	{128, "\x80\x01"},
+	{300, "\xac\x02"},
+	{16384, "\x80\x80\x01"},
+	{math.MaxInt32, "\xff\xff\xff\xff\x07"},
+	{-1, "\xff\xff\xff\xff\x0f"},
+	{math.MinInt32, "\x80\x80\x80\x80\x08"},
+}
+
+func TestDotNet7BitGolden(t *testing.T) {
+	for _, g := range dotNetGolden {
+		b := NewBuffer(5)
+		if err := b.Write7BitEncodedInt(g.v); err != nil {
+			t.Fatal(g.v, err)
+		}
+		b.Flip()
+		if string(b.Bytes()) != g.data {
+			t.Fatalf("%d: % x", g.v, b.Bytes())
+		}
+		if v, err := b.Read7BitEncodedInt(); err != nil || v != g.v || b.Remaining() != 0 {
+			t.Fatal(g.v, v, err)
+		}
+	}
+}
+
+func TestDotNet7BitInvalid(t *testing.T) {
+	for _, data := range []string{"", "\x80", "\xff\xff\xff\xff", "\x80\x00", "\xff\x80\x00",
+		"\xff\xff\xff\xff\x10", "\xff\xff\xff\xff\x8f\x01"} {
+		b := Wrap([]byte(data))
+		if _, err := b.Read7BitEncodedInt(); err == nil || b.Position() != 0 {
+			t.Fatalf("% x: %v", data, err)
+		}
+	}
+	b := NewBuffer(5)
+	for _, v := range []int{math.MaxInt32 + 1, math.MinInt32 - 1} {
+		if err := b.Write7BitEncodedInt(v); err == nil || b.Position() != 0 {
+			t.Fatal(v, err)
+		}
+	}
+}
+
+func TestDotNetStringGolden(t *testing.T) {
+	long := string(make([]byte, 200))
+	for _, g := range []struct{ s, data string }{
+		{"", "\x00"},
+		{"abc", "\x03abc"},
+		{"æøå", "\x06\xc3\xa6\xc3\xb8\xc3\xa5"},
+		{long, "\xc8\x01" + long},
+	} {
+		b := NewBuffer(256)
+		if err := b.WriteDotNetString(g.s); err != nil {
+			t.Fatal(err)
+		}
+		b.Flip()
+		if string(b.Bytes()) != g.data {
+			t.Fatalf("%q: % x", g.s, b.Bytes())
+		}
+		if s, err := b.ReadDotNetString(); err != nil || s != g.s {
+			t.Fatal(s, err)
+		}
+	}
+}
+
+func TestDotNetStringInvalid(t *testing.T) {
+	for _, data := range []string{"\x04abc", "\xff\xff\xff\xff\x0f", "\x80"} {
+		b := Wrap([]byte(data))
+		if _, err := b.ReadDotNetString(); err == nil || b.Position() != 0 {
+			t.Fatalf("% x: %v", data, err)
+		}
+	}
+	b := NewBuffer(3)
+	if err := b.WriteDotNetString("abc"); err == nil || b.Position() != 0 {
+		t.Fatal("WriteDotNetString should fail in 3 bytes", err)
+	}
+}
@@ -0,0 +1,38 @@
+package altdata
+
+import "encoding/hex"
+import "fmt"
+import "io"
+
+// Maximum number of bytes included by String.
+const maxStringBytes = 256
+
+// Implementing fmt.Stringer. Returns a header with position, limit, capacity
+// and byte order followed by a hex dump of up to 256 remaining bytes.
+// Offsets in the dump are relative to the position.
+func (b *Buffer) String() string {
+	data := b.Bytes()
+	str := b.header() + hex.Dump(data[:min(len(data), maxStringBytes)])
+	if len(data) > maxStringBytes {
+		str += "...\n"
+	}
+	return str
+}
+
+// Writes the header and a hex dump of all remaining bytes to w.
+// DOES NOT move the position.
+func (b *Buffer) Dump(w io.Writer) error {
+	if _, err := io.WriteString(w, b.header()); err != nil {
+		return err
+	}
+	dumper := hex.Dumper(w)
+	if _, err := dumper.Write(b.Bytes()); err != nil {
+		return err
+	}
+	return dumper.Close()
+}
+
+func (b *Buffer) header() string {
+	return fmt.Sprintf("Buffer position: %d limit: %d capacity: %d order: %v\n",
+		b.position, b.limit, cap(b.data), b.order)
+}
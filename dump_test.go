@@ -0,0 +1,40 @@
+package altdata
+
+import "encoding/hex"
+import "strings"
+import "testing"
+
+func TestStringTruncation(t *testing.T) {
+	for _, n := range []int{0, maxStringBytes - 1, maxStringBytes, maxStringBytes + 1, 2 * maxStringBytes} {
+		b := NewBuffer(n + 1)
+		b.Fill('a', n)
+		b.Flip()
+		str := b.String()
+		header, dump, _ := strings.Cut(str, "\n")
+		if header != strings.TrimSuffix(b.header(), "\n") {
+			t.Fatal(n, header)
+		}
+		want := hex.Dump(b.Bytes()[:min(n, maxStringBytes)])
+		if n > maxStringBytes {
+			want += "...\n"
+		}
+		if dump != want {
+			t.Fatalf("%d: %q", n, dump)
+		}
+		var sb strings.Builder
+		if err := b.Dump(&sb); err != nil || sb.String() != b.header()+hex.Dump(b.Bytes()) {
+			t.Fatal(n, err)
+		}
+		if b.Position() != 0 || b.Remaining() != n {
+			t.Fatal(n, b.Position(), b.Remaining())
+		}
+	}
+}
+
+func TestStringRelativeOffsets(t *testing.T) {
+	b := Wrap([]byte("abcd"))
+	b.Skip(2)
+	if !strings.HasPrefix(b.String(), "Buffer position: 2 limit: 4 capacity: 4 order: LittleEndian\n00000000  63 64 ") {
+		t.Fatalf("%q", b.String())
+	}
+}
@@ -0,0 +1,85 @@
+package altdata
+
+// Returns the end of the data and how far it can extend without growing,
+// the position and limit while writing, otherwise the limit and capacity.
+func (b *Buffer) dataEnd() (end, bound int) {
+	if b.mode == ModeWrite && !b.append {
+		return b.position, b.limit
+	}
+	return b.limit, cap(b.data)
+}
+
+// Inserts p at the absolute offset, moving the data after offset right by len(p).
+// In read and append mode the data is [position:limit] and the limit is extended,
+// a position after offset is moved along with its byte. In write mode the data is
+// the bytes written before the position, which is moved past the inserted bytes.
+// Grows the buffer if growable or in append mode, otherwise returns an error
+// if there is not space enough. Like Delete it edits the data in any Mode.
+func (b *Buffer) Insert(p []byte, offset int) error {
+	if b.strict {
+		b.check()
+	}
+	end, bound := b.dataEnd()
+	if offset < 0 || offset > end {
+		return ErrOutOfRange
+	}
+	if end+len(p) > bound {
+		if !b.growable && !b.append {
+			return ErrBufferFull
+		}
+		size := cap(b.data)
+		if !b.ensureCapacity(end + len(p)) {
+			return ErrMaxCapacity
+		}
+		if b.limit == size && b.mode == ModeWrite && !b.append {
+			b.limit = cap(b.data)
+		}
+		if _, bound = b.dataEnd(); end+len(p) > bound {
+			return ErrBufferFull
+		}
+	}
+	copy(b.data[offset+len(p):], b.data[offset:end])
+	copy(b.data[offset:], p)
+	if b.mode == ModeWrite && !b.append {
+		b.position += len(p)
+		return nil
+	}
+	b.limit += len(p)
+	if b.position > offset {
+		b.position += len(p)
+	}
+	return nil
+}
+
+// Removes n bytes at the absolute offset, moving the data after them left.
+// In read and append mode the limit is reduced and a position inside the removed
+// range is moved to offset. In write mode the position is moved back by n.
+func (b *Buffer) Delete(offset, n int) error {
+	if b.strict {
+		b.check()
+	}
+	end, _ := b.dataEnd()
+	if offset < 0 || n < 0 || offset+n > end {
+		return ErrOutOfRange
+	}
+	copy(b.data[offset:], b.data[offset+n:end])
+	if b.mode == ModeWrite && !b.append {
+		b.position -= n
+		b.checkMark()
+		return nil
+	}
+	b.limit -= n
+	if b.position > offset+n {
+		b.position -= n
+	} else if b.position > offset {
+		b.position = offset
+	}
+	b.checkMark()
+	return nil
+}
+
+// Inserts p in front of the remaining bytes, so they follow p when read.
+// Same as Insert(p, position).
+func (b *Buffer) Prepend(p []byte) error {
+	return b.Insert(p, b.position)
+}
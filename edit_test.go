@@ -0,0 +1,177 @@
+package altdata
+
+import "testing"
+
+func TestInsertShift(t *testing.T) {
+	b := Wrap(append([]byte("abcdef"), make([]byte, 6)...))
+	b.SetManual(4, 6)
+	if err := b.Insert([]byte("XYZ"), 1); err != nil {
+		t.Fatal(err)
+	}
+	if b.Limit() != 9 || b.Position() != 7 || string(b.data[:9]) != "aXYZbcdef" {
+		t.Fatal(b.Position(), b.Limit(), string(b.data[:9]))
+	}
+	// The shift overlaps the moved bytes.
+	if err := b.Insert([]byte("12"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if b.Limit() != 11 || string(b.data[:11]) != "12aXYZbcdef" || b.Position() != 9 {
+		t.Fatal(b.Position(), string(b.data[:11]))
+	}
+	if err := b.Insert(nil, 3); err != nil || b.Limit() != 11 {
+		t.Fatal(err)
+	}
+	if err := b.Insert([]byte("!"), 11); err != nil || string(b.data[:12]) != "12aXYZbcdef!" {
+		t.Fatal(err, string(b.data))
+	}
+	if err := b.Insert([]byte("?"), 0); err != ErrBufferFull || b.Limit() != 12 {
+		t.Fatal(err)
+	}
+	for _, offset := range []int{-1, 13} {
+		if err := b.Insert([]byte("?"), offset); err != ErrOutOfRange {
+			t.Fatal(offset, err)
+		}
+	}
+}
+
+func TestInsertPositionAtOffset(t *testing.T) {
+	b := Wrap(append([]byte("abcd"), 0, 0))
+	b.SetManual(2, 4)
+	b.Insert([]byte("XY"), 2)
+	if b.Position() != 2 {
+		t.Fatal(b.Position())
+	}
+	if string(b.Bytes()) != "XYcd" {
+		t.Fatal(string(b.Bytes()))
+	}
+}
+
+func TestInsertGrow(t *testing.T) {
+	b := NewGrowableBuffer(4)
+	b.WriteString("abcd")
+	b.Flip()
+	if err := b.Insert([]byte("0123456789"), 2); err != nil {
+		t.Fatal(err)
+	}
+	if b.Capacity() < 14 || b.Limit() != 14 || string(b.Bytes()) != "ab0123456789cd" {
+		t.Fatal(b.Capacity(), string(b.Bytes()))
+	}
+
+	m := NewGrowableBuffer(4)
+	m.SetGrowthPolicy(2, 8)
+	m.WriteString("abcd")
+	m.Flip()
+	if err := m.Insert([]byte("0123456789"), 0); err != ErrMaxCapacity || string(m.Bytes()) != "abcd" {
+		t.Fatal(err, string(m.Bytes()))
+	}
+}
+
+func TestDeleteShift(t *testing.T) {
+	b := Wrap([]byte("0123456789"))
+	b.SetManual(8, 10)
+	if err := b.Delete(2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if b.Limit() != 7 || b.Position() != 5 || string(b.data[:7]) != "0156789" {
+		t.Fatal(b.Position(), b.Limit(), string(b.data[:7]))
+	}
+	// Position inside the removed range moves to offset.
+	b.SetManual(3, 7)
+	if err := b.Delete(1, 4); err != nil || b.Position() != 1 || string(b.data[:3]) != "089" {
+		t.Fatal(err, b.Position(), string(b.data[:3]))
+	}
+	b.SetManual(3, 3)
+	if err := b.Delete(0, 3); err != nil || b.Position() != 0 || b.Limit() != 0 {
+		t.Fatal(err, b.Position(), b.Limit())
+	}
+	for _, c := range [][2]int{{-1, 1}, {0, -1}, {0, 1}} {
+		if err := b.Delete(c[0], c[1]); err != ErrOutOfRange {
+			t.Fatal(c, err)
+		}
+	}
+}
+
+func TestDeleteMark(t *testing.T) {
+	b := Wrap([]byte("0123456789"))
+	b.SetManual(9, 10)
+	b.Mark()
+	b.Delete(0, 5)
+	if catchPanic(b.Reset) == nil {
+		t.Fatal("Reset should panic when the mark was past the new position")
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	b := NewGrowableBuffer(4)
+	b.WriteString("body")
+	b.Flip()
+	b.Skip(1)
+	if err := b.Prepend([]byte("header:")); err != nil {
+		t.Fatal(err)
+	}
+	if b.Position() != 1 || string(b.Bytes()) != "header:ody" || string(b.data[:1]) != "b" {
+		t.Fatal(b.Position(), string(b.data[:b.Limit()]))
+	}
+	if err := b.Prepend(nil); err != nil || string(b.Bytes()) != "header:ody" {
+		t.Fatal(err, string(b.Bytes()))
+	}
+
+	f := Wrap([]byte("data"))
+	if err := f.Prepend([]byte("x")); err != ErrBufferFull || string(f.Bytes()) != "data" {
+		t.Fatal(err, string(f.Bytes()))
+	}
+}
+
+func TestInsertWriteMode(t *testing.T) {
+	b := NewBuffer(8)
+	b.WriteString("body")
+	if err := b.Insert([]byte("hd:"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if b.Position() != 7 || b.Limit() != 8 {
+		t.Fatal(b.Position(), b.Limit())
+	}
+	b.WriteByte('!')
+	if err := b.Insert([]byte("x"), 0); err != ErrBufferFull || b.Position() != 8 {
+		t.Fatal(err)
+	}
+	if err := b.Insert([]byte("x"), 9); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != "hd:body!" {
+		t.Fatal(string(b.Bytes()))
+	}
+}
+
+func TestInsertWriteModeGrowable(t *testing.T) {
+	b := NewGrowableBuffer(8)
+	b.WriteString("ab")
+	if err := b.Insert([]byte("123"), 1); err != nil || b.Capacity() != 8 {
+		t.Fatal("insert that fits should not grow", err, b.Capacity())
+	}
+	if err := b.Insert([]byte("456789"), 5); err != nil || b.Capacity() < 11 || b.Limit() != b.Capacity() {
+		t.Fatal(err, b.Capacity(), b.Limit())
+	}
+	b.WriteString("c")
+	b.Flip()
+	if string(b.Bytes()) != "a123b456789c" {
+		t.Fatal(string(b.Bytes()))
+	}
+}
+
+func TestDeleteWriteMode(t *testing.T) {
+	b := NewBuffer(8)
+	b.WriteString("abcdef")
+	if err := b.Delete(1, 2); err != nil || b.Position() != 4 || b.Limit() != 8 {
+		t.Fatal(err, b.Position(), b.Limit())
+	}
+	if err := b.Delete(3, 2); err != ErrOutOfRange {
+		t.Fatal("delete past the written data should fail", err)
+	}
+	b.WriteString("g")
+	b.Flip()
+	if string(b.Bytes()) != "adefg" {
+		t.Fatal(string(b.Bytes()))
+	}
+}
@@ -0,0 +1,39 @@
+package altdata
+
+import "encoding/json"
+
+// Implementing encoding.BinaryMarshaler. Returns a copy of the remaining bytes.
+// The position and byte order are not included.
+func (b *Buffer) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, b.Bytes()...), nil
+}
+
+// Implementing encoding.BinaryUnmarshaler. Replaces the buffer with a copy of data,
+// ready for reading with LittleEndian byte order.
+func (b *Buffer) UnmarshalBinary(data []byte) error {
+	*b = *Wrap(append([]byte{}, data...))
+	return nil
+}
+
+// Implementing json.Marshaler. The remaining bytes are encoded as a base64 string
+// like a []byte, an empty buffer is "".
+func (b *Buffer) MarshalJSON() ([]byte, error) {
+	if b.position >= b.limit {
+		return []byte(`""`), nil
+	}
+	return json.Marshal(b.Bytes())
+}
+
+// Implementing json.Unmarshaler. Replaces the buffer with the bytes decoded from
+// a base64 string, ready for reading with LittleEndian byte order.
+func (b *Buffer) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var p []byte
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*b = *Wrap(p)
+	return nil
+}
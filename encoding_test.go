@@ -0,0 +1,56 @@
+package altdata
+
+import "encoding"
+import "encoding/json"
+import "testing"
+
+var _ encoding.BinaryMarshaler = (*Buffer)(nil)
+var _ encoding.BinaryUnmarshaler = (*Buffer)(nil)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	b := Wrap([]byte("xabc"))
+	b.Skip(1)
+	data, err := b.MarshalBinary()
+	if err != nil || string(data) != "abc" {
+		t.Fatal(string(data), err)
+	}
+	data[0] = 'z'
+	if b.data[1] != 'a' {
+		t.Fatal("MarshalBinary should copy")
+	}
+	var r Buffer
+	if err := r.UnmarshalBinary(data); err != nil || string(r.Bytes()) != "zbc" {
+		t.Fatal(string(r.Bytes()), err)
+	}
+	data[1] = 'y'
+	if v, _ := r.ReadUint8(); v != 'z' || string(r.Bytes()) != "bc" {
+		t.Fatal("UnmarshalBinary should copy")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	b := Wrap([]byte{0, 1, 2, 0xff})
+	data, err := json.Marshal(b)
+	if err != nil || string(data) != `"AAEC/w=="` {
+		t.Fatal(string(data), err)
+	}
+	var r Buffer
+	if err := json.Unmarshal(data, &r); err != nil || !r.Equal(b) {
+		t.Fatal(r.Bytes(), err)
+	}
+}
+
+func TestJSONEmpty(t *testing.T) {
+	b := NewBuffer(4)
+	b.Flip()
+	if data, err := json.Marshal(b); err != nil || string(data) != `""` {
+		t.Fatal(string(data), err)
+	}
+	r := Wrap([]byte("abc"))
+	if err := json.Unmarshal([]byte("null"), r); err != nil || string(r.Bytes()) != "abc" {
+		t.Fatal("null should leave the buffer unchanged", err)
+	}
+	if err := json.Unmarshal([]byte(`""`), r); err != nil || r.Remaining() != 0 {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+package altdata
+
+import "errors"
+
+// Reads a bit field of width bytes, 1, 2, 4 or 8, using the byte order.
+// Returns an error and does not move if not enough bytes remain.
+func (b *Buffer) ReadFlags(width int) (uint64, error) {
+	switch width {
+	case 1:
+		v, err := b.ReadUint8()
+		return uint64(v), err
+	case 2:
+		v, err := b.ReadUint16()
+		return uint64(v), err
+	case 4:
+		v, err := b.ReadUint32()
+		return uint64(v), err
+	case 8:
+		return b.ReadUint64()
+	}
+	return 0, errors.New("Flags width must be 1, 2, 4 or 8")
+}
+
+// Writes the bit field v as width bytes, 1, 2, 4 or 8, using the byte order.
+// Returns an error and writes nothing if v has bits set beyond width
+// or there is not space enough.
+func (b *Buffer) WriteFlags(v uint64, width int) error {
+	if width != 1 && width != 2 && width != 4 && width != 8 {
+		return errors.New("Flags width must be 1, 2, 4 or 8")
+	}
+	if width < 8 && v>>(width*8) != 0 {
+		return errors.New("Flags do not fit in width")
+	}
+	switch width {
+	case 1:
+		return b.WriteUint8(uint8(v))
+	case 2:
+		return b.WriteUint16(uint16(v))
+	case 4:
+		return b.WriteUint32(uint32(v))
+	}
+	return b.WriteUint64(v)
+}
+
+// Reports whether all bits of flag are set in v.
+func HasFlag(v, flag uint64) bool {
+	return v&flag == flag
+}
+
+// Returns v with the bits of flag set.
+func SetFlag(v, flag uint64) uint64 {
+	return v | flag
+}
+
+// Returns v with the bits of flag cleared.
+func ClearFlag(v, flag uint64) uint64 {
+	return v &^ flag
+}
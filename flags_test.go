@@ -0,0 +1,57 @@
+package altdata
+
+import "testing"
+
+func TestFlagsHighBit(t *testing.T) {
+	for _, order := range orders {
+		for _, w := range []int{1, 2, 4, 8} {
+			b := NewBufferWithOrder(8, order)
+			high := uint64(1) << (w*8 - 1)
+			v := SetFlag(1, high)
+			if err := b.WriteFlags(v, w); err != nil || b.Position() != w {
+				t.Fatal(w, err)
+			}
+			b.Flip()
+			// The high bit is in the last byte for little endian and the first for big endian.
+			first, last := b.data[0], b.data[w-1]
+			if w > 1 && order == orders[0] && (first != 1 || last != 0x80) {
+				t.Fatalf("%v width %d: % x", order, w, b.Bytes())
+			}
+			if w > 1 && order == orders[1] && (first != 0x80 || last != 1) {
+				t.Fatalf("%v width %d: % x", order, w, b.Bytes())
+			}
+			r, err := b.ReadFlags(w)
+			if err != nil || r != v || !HasFlag(r, high) || !HasFlag(r, 1|high) {
+				t.Fatal(w, r, err)
+			}
+			if c := ClearFlag(r, high); c != 1 || HasFlag(c, high) {
+				t.Fatal(w, c)
+			}
+		}
+	}
+}
+
+func TestFlagsInvalid(t *testing.T) {
+	b := NewBuffer(8)
+	for _, w := range []int{0, 3, 16} {
+		if err := b.WriteFlags(1, w); err == nil {
+			t.Fatal(w)
+		}
+	}
+	for w, v := range map[int]uint64{1: 0x100, 2: 0x10000, 4: 0x100000000} {
+		if err := b.WriteFlags(v, w); err == nil || b.Position() != 0 {
+			t.Fatal(w, err)
+		}
+	}
+	b.WriteUint16(7)
+	b.Flip()
+	if _, err := b.ReadFlags(3); err == nil || b.Position() != 0 {
+		t.Fatal(err)
+	}
+	if _, err := b.ReadFlags(4); err == nil || b.Position() != 0 {
+		t.Fatal("ReadFlags should fail with 2 bytes", err)
+	}
+	if !HasFlag(0, 0) || HasFlag(0b01, 0b11) {
+		t.Fatal("HasFlag needs all bits")
+	}
+}
@@ -0,0 +1,71 @@
+package altdata
+
+import "math"
+
+// Reads an IEEE 754 half precision float using the byte order.
+func (b *Buffer) ReadFloat16() (float32, error) {
+	h, err := b.ReadUint16()
+	return float16to32(h), err
+}
+
+// Writes v as an IEEE 754 half precision float using the byte order.
+// v is rounded to nearest even, values too large become Inf.
+func (b *Buffer) WriteFloat16(v float32) error {
+	return b.WriteUint16(float32to16(v))
+}
+
+func float16to32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h) & 0x3ff
+	switch exp {
+	case 0:
+		// Zero or subnormal, mant * 2^-24.
+		v := float32(mant) / (1 << 24)
+		if sign != 0 {
+			v = -v
+		}
+		return v
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp-15+127)<<23 | mant<<13)
+}
+
+func float32to16(v float32) uint16 {
+	bits := math.Float32bits(v)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+	if exp == 0xff {
+		if mant != 0 {
+			// Keep NaN a NaN, quiet bit set.
+			return sign | 0x7e00 | uint16(mant>>13)
+		}
+		return sign | 0x7c00
+	}
+	e := exp - 127 + 15
+	if e >= 0x1f {
+		return sign | 0x7c00
+	}
+	if e <= 0 {
+		if e < -10 {
+			return sign
+		}
+		// Subnormal, including the implicit leading bit.
+		return sign | uint16(roundShift(mant|0x800000, uint(14-e)))
+	}
+	// A carry from rounding goes into the exponent, possibly up to Inf.
+	return sign | uint16(roundShift(uint32(e)<<23|mant, 13))
+}
+
+// Returns v >> shift rounded to nearest even.
+func roundShift(v uint32, shift uint) uint32 {
+	result := v >> shift
+	rem := v & (1<<shift - 1)
+	half := uint32(1) << (shift - 1)
+	if rem > half || rem == half && result&1 == 1 {
+		result++
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package altdata
+
+import "math"
+import "testing"
+
+var float16Golden = []struct {
+	h uint16
+	v float32
+}{
+	{0x0000, 0},
+	{0x0001, 1.0 / (1 << 24)},
+	{0x03ff, 1023.0 / (1 << 24)},
+	{0x0400, 1.0 / (1 << 14)},
+	{0x3c00, 1},
+	{0x3c01, 1 + 1.0/1024},
+	{0xc000, -2},
+	{0x3555, 0.333251953125},
+	{0x7bff, 65504},
+	{0x7c00, float32(math.Inf(1))},
+	{0xfc00, float32(math.Inf(-1))},
+}
+
+func TestFloat16Golden(t *testing.T) {
+	for _, g := range float16Golden {
+		for _, order := range orders {
+			b := NewBufferWithOrder(2, order)
+			if err := b.WriteFloat16(g.v); err != nil {
+				t.Fatal(err)
+			}
+			b.Flip()
+			if h, _ := b.PeekUint16(); h != g.h {
+				t.Fatalf("%v: got %#04x want %#04x", g.v, h, g.h)
+			}
+			if v, err := b.ReadFloat16(); err != nil || v != g.v {
+				t.Fatal(g.v, v, err)
+			}
+		}
+	}
+}
+
+func TestFloat16Rounding(t *testing.T) {
+	for _, c := range []struct {
+		v float32
+		h uint16
+	}{
+		{65519, 0x7bff},
+		{65520, 0x7c00},
+		{1e10, 0x7c00},
+		{-1e10, 0xfc00},
+		{1.0 / (1 << 25), 0x0000},
+		{1.5 / (1 << 24), 0x0002},
+		{1.0 / (1 << 26), 0x0000},
+		{1 + 1.0/2048, 0x3c00},
+		{1 + 3.0/2048, 0x3c02},
+		{float32(math.Copysign(0, -1)), 0x8000},
+	} {
+		if h := float32to16(c.v); h != c.h {
+			t.Fatalf("%v: got %#04x want %#04x", c.v, h, c.h)
+		}
+	}
+}
+
+func TestFloat16NaN(t *testing.T) {
+	nan := float32(math.NaN())
+	h := float32to16(nan)
+	if h&0x7c00 != 0x7c00 || h&0x3ff == 0 {
+		t.Fatalf("%#04x is not a NaN", h)
+	}
+	for _, h := range []uint16{0x7c01, 0x7e00, 0xffff} {
+		if v := float16to32(h); v == v {
+			t.Fatalf("%#04x decoded to %v", h, v)
+		}
+	}
+	// A NaN with only low mantissa bits stays a NaN.
+	if h := float32to16(math.Float32frombits(0x7f800001)); h&0x3ff == 0 {
+		t.Fatalf("%#04x is not a NaN", h)
+	}
+	if v := float16to32(0x8000); v != 0 || !math.Signbit(float64(v)) {
+		t.Fatal("0x8000 should decode to -0", v)
+	}
+}
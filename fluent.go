@@ -0,0 +1,113 @@
+package altdata
+
+// Chainable write methods. The first error is kept and every following Put
+// is a no-op, so a single check of Err after a chain is sufficient:
+//
+//	err := b.PutUint16(x).PutUint32(y).PutString(s).Err()
+
+// Returns the first error from a Put method since the last Clear.
+func (b *Buffer) Err() error {
+	return b.err
+}
+
+// Chainable WriteUint8.
+func (b *Buffer) PutUint8(v uint8) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteUint8(v)
+	}
+	return b
+}
+
+// Chainable WriteInt8.
+func (b *Buffer) PutInt8(v int8) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteInt8(v)
+	}
+	return b
+}
+
+// Chainable WriteUint16.
+func (b *Buffer) PutUint16(v uint16) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteUint16(v)
+	}
+	return b
+}
+
+// Chainable WriteInt16.
+func (b *Buffer) PutInt16(v int16) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteInt16(v)
+	}
+	return b
+}
+
+// Chainable WriteUint32.
+func (b *Buffer) PutUint32(v uint32) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteUint32(v)
+	}
+	return b
+}
+
+// Chainable WriteInt32.
+func (b *Buffer) PutInt32(v int32) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteInt32(v)
+	}
+	return b
+}
+
+// Chainable WriteUint64.
+func (b *Buffer) PutUint64(v uint64) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteUint64(v)
+	}
+	return b
+}
+
+// Chainable WriteInt64.
+func (b *Buffer) PutInt64(v int64) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteInt64(v)
+	}
+	return b
+}
+
+// Chainable WriteFloat32.
+func (b *Buffer) PutFloat32(v float32) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteFloat32(v)
+	}
+	return b
+}
+
+// Chainable WriteFloat64.
+func (b *Buffer) PutFloat64(v float64) *Buffer {
+	if b.err == nil {
+		b.err = b.WriteFloat64(v)
+	}
+	return b
+}
+
+// Chainable write of all of p, an error if it does not fit.
+func (b *Buffer) PutBytes(p []byte) *Buffer {
+	if b.err == nil {
+		var data []byte
+		if data, b.err = b.reserve(len(p)); b.err == nil {
+			copy(data, p)
+		}
+	}
+	return b
+}
+
+// Chainable write of all of s, an error if it does not fit.
+func (b *Buffer) PutString(s string) *Buffer {
+	if b.err == nil {
+		var data []byte
+		if data, b.err = b.reserve(len(s)); b.err == nil {
+			copy(data, s)
+		}
+	}
+	return b
+}
@@ -0,0 +1,166 @@
+package altdata
+
+import "encoding/binary"
+import "errors"
+import "fmt"
+import "math"
+import "unsafe"
+
+// Fixed size numeric types supported by ReadValue and WriteValue.
+type Fixed interface {
+	~int8 | ~uint8 | ~int16 | ~uint16 | ~int32 | ~uint32 | ~int64 | ~uint64 | ~float32 | ~float64
+}
+
+// Reads a T using the byte order of b, without the reflection of ReadBinary.
+// Returns an error and does not move if not enough bytes remain.
+func ReadValue[T Fixed](b *Buffer) (T, error) {
+	var v T
+	p, err := b.read(int(unsafe.Sizeof(v)))
+	if err != nil {
+		return v, err
+	}
+	b.get(unsafe.Pointer(&v), p)
+	return v, nil
+}
+
+// Writes v using the byte order of b, without the reflection of WriteBinary.
+// Returns an error and does not move if there is not space enough.
+func WriteValue[T Fixed](b *Buffer, v T) error {
+	p, err := b.reserve(int(unsafe.Sizeof(v)))
+	if err != nil {
+		return err
+	}
+	b.put(unsafe.Pointer(&v), p)
+	return nil
+}
+
+// Reads n values of type T using the byte order of b.
+// Returns an error and does not move if not enough bytes remain.
+func ReadSlice[T Fixed](b *Buffer, n int) ([]T, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return nil, err
+	}
+	var v T
+	size := int(unsafe.Sizeof(v))
+	if n < 0 || n > (b.limit-b.position)/size {
+		return nil, ErrBufferEmpty
+	}
+	p := b.Next(n * size)
+	s := make([]T, n)
+	if b.hostOrder() {
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(s))), len(p)), p)
+		return s, nil
+	}
+	for i := range s {
+		b.get(unsafe.Pointer(&s[i]), p[i*size:(i+1)*size])
+	}
+	return s, nil
+}
+
+// Writes all values of s using the byte order of b.
+// Returns an error and does not move if there is not space enough.
+func WriteSlice[T Fixed](b *Buffer, s []T) error {
+	var v T
+	size := int(unsafe.Sizeof(v))
+	p, err := b.reserve(len(s) * size)
+	if err != nil {
+		return err
+	}
+	if b.hostOrder() {
+		copy(p, unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(s))), len(p)))
+		return nil
+	}
+	for i := range s {
+		b.put(unsafe.Pointer(&s[i]), p[i*size:(i+1)*size])
+	}
+	return nil
+}
+
+// Writes the number of items as a uint32 followed by each item written by enc.
+// Returns an error and writes nothing if enc fails or there is not space enough.
+func WriteList[T any](b *Buffer, items []T, enc func(*Buffer, T) error) error {
+	if uint64(len(items)) > math.MaxUint32 {
+		return errors.New("Too many items for uint32 count")
+	}
+	start := b.writePosition()
+	if err := b.WriteUint32(uint32(len(items))); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := enc(b, item); err != nil {
+			b.setWritePosition(start)
+			return err
+		}
+	}
+	return nil
+}
+
+// Reads a uint32 count followed by that many items read by dec.
+// As every item is assumed to take at least one byte, a count larger than the
+// remaining bytes is an error, so a bad count can not cause a huge allocation.
+// Returns an error and does not move if dec fails or the count is too large.
+func ReadList[T any](b *Buffer, dec func(*Buffer) (T, error)) ([]T, error) {
+	start := b.position
+	count, err := b.ReadUint32()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(count) > uint64(b.limit-b.position) {
+		b.position = start
+		return nil, fmt.Errorf("Count exceeds remaining bytes in buffer: %w", ErrBufferEmpty)
+	}
+	items := make([]T, 0, count)
+	for range count {
+		item, err := dec(b)
+		if err != nil {
+			b.position = start
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Decodes p into the value of size len(p) pointed to by v.
+func (b *Buffer) get(v unsafe.Pointer, p []byte) {
+	switch len(p) {
+	case 1:
+		*(*uint8)(v) = p[0]
+	case 2:
+		*(*uint16)(v) = b.order.Uint16(p)
+	case 4:
+		*(*uint32)(v) = b.order.Uint32(p)
+	case 8:
+		*(*uint64)(v) = b.order.Uint64(p)
+	}
+}
+
+// Encodes the value of size len(p) pointed to by v into p.
+func (b *Buffer) put(v unsafe.Pointer, p []byte) {
+	switch len(p) {
+	case 1:
+		p[0] = *(*uint8)(v)
+	case 2:
+		b.order.PutUint16(p, *(*uint16)(v))
+	case 4:
+		b.order.PutUint32(p, *(*uint32)(v))
+	case 8:
+		b.order.PutUint64(p, *(*uint64)(v))
+	}
+}
+
+// True if the host is little endian.
+var hostLittleEndian = *(*byte)(unsafe.Pointer(&[]uint16{1}[0])) == 1
+
+// Reports whether the byte order is the same as the host, so values can be copied directly.
+func (b *Buffer) hostOrder() bool {
+	switch b.order {
+	case binary.LittleEndian:
+		return hostLittleEndian
+	case binary.BigEndian:
+		return !hostLittleEndian
+	case binary.NativeEndian:
+		return true
+	}
+	return false
+}
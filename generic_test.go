@@ -0,0 +1,167 @@
+package altdata
+
+import "encoding/binary"
+import "errors"
+import "math"
+import "testing"
+
+func TestWriteSliceMatchesWriteValue(t *testing.T) {
+	values := []int32{0, 1, -1, math.MaxInt32, math.MinInt32, 0x01020304}
+	// One of the orders is the host order and uses the bulk copy.
+	for _, order := range orders {
+		bulk := NewBufferWithOrder(64, order)
+		single := NewBufferWithOrder(64, order)
+		if err := WriteSlice(bulk, values); err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range values {
+			WriteValue(single, v)
+		}
+		bulk.Flip()
+		single.Flip()
+		if !bulk.Equal(single) {
+			t.Fatalf("%s: % x != % x", order, bulk.Bytes(), single.Bytes())
+		}
+		s, err := ReadSlice[int32](bulk, len(values))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range values {
+			if r, _ := ReadValue[int32](single); r != v || s[i] != v {
+				t.Fatal(order, v, r, s[i])
+			}
+		}
+	}
+}
+
+func TestFloat64SliceOrders(t *testing.T) {
+	values := []float64{1.5, math.Inf(-1), -0.25}
+	for _, order := range orders {
+		b := NewBufferWithOrder(64, order)
+		b.WriteFloat64Slice(values)
+		b.Flip()
+		if v, _ := b.PeekBytes(8); order.Uint64(v) != math.Float64bits(1.5) {
+			t.Fatalf("%s: % x", order, v)
+		}
+		s, err := b.ReadFloat64Slice(3)
+		if err != nil || s[0] != 1.5 || !math.IsInf(s[1], -1) || s[2] != -0.25 {
+			t.Fatal(order, s, err)
+		}
+	}
+}
+
+func TestReadSliceShort(t *testing.T) {
+	b := Wrap([]byte{1, 2, 3})
+	if _, err := ReadSlice[uint16](b, 2); err == nil || b.Position() != 0 {
+		t.Fatal(err)
+	}
+	if _, err := ReadSlice[uint16](b, -1); err == nil {
+		t.Fatal("negative count should fail")
+	}
+	if err := WriteSlice(b, []uint32{1}); err == nil || b.Position() != 0 {
+		t.Fatal(err)
+	}
+}
+
+var benchValues = make([]uint32, 1024)
+
+func BenchmarkWriteValueLoop(b *testing.B) {
+	buffer := NewBuffer(4 * len(benchValues))
+	for i := 0; i < b.N; i++ {
+		buffer.Clear()
+		for _, v := range benchValues {
+			WriteValue(buffer, v)
+		}
+	}
+}
+
+func BenchmarkWriteSliceHostOrder(b *testing.B) {
+	buffer := NewBufferWithOrder(4*len(benchValues), binary.NativeEndian)
+	for i := 0; i < b.N; i++ {
+		buffer.Clear()
+		WriteSlice(buffer, benchValues)
+	}
+}
+
+func BenchmarkWriteSliceSwapped(b *testing.B) {
+	var order binary.ByteOrder = binary.BigEndian
+	if !hostLittleEndian {
+		order = binary.LittleEndian
+	}
+	buffer := NewBufferWithOrder(4*len(benchValues), order)
+	for i := 0; i < b.N; i++ {
+		buffer.Clear()
+		WriteSlice(buffer, benchValues)
+	}
+}
+
+func BenchmarkReadValueLoop(b *testing.B) {
+	buffer := NewBuffer(4 * len(benchValues))
+	WriteSlice(buffer, benchValues)
+	for i := 0; i < b.N; i++ {
+		buffer.Rewind()
+		for range benchValues {
+			ReadValue[uint32](buffer)
+		}
+	}
+}
+
+func BenchmarkReadSlice(b *testing.B) {
+	buffer := NewBuffer(4 * len(benchValues))
+	WriteSlice(buffer, benchValues)
+	for i := 0; i < b.N; i++ {
+		buffer.Rewind()
+		ReadSlice[uint32](buffer, len(benchValues))
+	}
+}
+
+func encodeLString(b *Buffer, s string) error { return b.WriteLString(s) }
+
+func decodeLString(b *Buffer) (string, error) { return b.ReadLString() }
+
+func TestListRoundTrip(t *testing.T) {
+	b := NewBuffer(64)
+	if err := WriteList(b, []string{"a", "bc", ""}, encodeLString); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteList(b, nil, encodeLString); err != nil {
+		t.Fatal(err)
+	}
+	b.Flip()
+	items, err := ReadList(b, decodeLString)
+	if err != nil || len(items) != 3 || items[0] != "a" || items[1] != "bc" || items[2] != "" {
+		t.Fatal(items, err)
+	}
+	if items, err := ReadList(b, decodeLString); err != nil || len(items) != 0 || b.Remaining() != 0 {
+		t.Fatal(items, err)
+	}
+}
+
+func TestListTruncated(t *testing.T) {
+	b := NewBuffer(64)
+	WriteList(b, []string{"a", "bc", ""}, encodeLString)
+	b.Flip()
+	length := b.Limit()
+	for n := 0; n < length; n++ {
+		b.SetManual(0, n)
+		if _, err := ReadList(b, decodeLString); err == nil || b.Position() != 0 {
+			t.Fatal(n, err)
+		}
+	}
+}
+
+func TestListOversizedCount(t *testing.T) {
+	h := Wrap([]byte{0xff, 0xff, 0xff, 0xff, 1})
+	if _, err := ReadList(h, decodeLString); !errors.Is(err, ErrBufferEmpty) || h.Position() != 0 {
+		t.Fatal(err)
+	}
+	// A count fitting the remaining bytes is still rejected when the items run out.
+	c := Wrap([]byte{3, 0, 0, 0, 1, 2})
+	if _, err := ReadList(c, (*Buffer).ReadByte); err == nil || c.Position() != 0 {
+		t.Fatal(err)
+	}
+	s := NewBuffer(6)
+	if err := WriteList(s, []string{"abc"}, encodeLString); err == nil || s.Position() != 0 {
+		t.Fatal(err)
+	}
+}
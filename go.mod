@@ -0,0 +1,3 @@
+module github.com/vildninja/altdata
+
+go 1.23
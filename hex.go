@@ -0,0 +1,50 @@
+package altdata
+
+import "encoding/hex"
+import "errors"
+
+const upperHex = "0123456789ABCDEF"
+
+// Writes data hex encoded in lowercase. Returns the number of hex characters written.
+// Returns an error and writes nothing if it does not fit.
+func (b *Buffer) WriteHex(data []byte) (int, error) {
+	p, err := b.reserve(hex.EncodedLen(len(data)))
+	if err != nil {
+		return 0, err
+	}
+	return hex.Encode(p, data), nil
+}
+
+// Same as WriteHex but in uppercase.
+func (b *Buffer) WriteHexUpper(data []byte) (int, error) {
+	p, err := b.reserve(hex.EncodedLen(len(data)))
+	if err != nil {
+		return 0, err
+	}
+	for i, c := range data {
+		p[2*i] = upperHex[c>>4]
+		p[2*i+1] = upperHex[c&0x0f]
+	}
+	return len(p), nil
+}
+
+// Reads nHexChars hex characters of either case and returns the decoded bytes.
+// Returns an error and does not move if nHexChars is odd, exceeds the remaining
+// bytes, or the characters are not valid hex.
+func (b *Buffer) ReadHex(nHexChars int) ([]byte, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return nil, err
+	}
+	if nHexChars < 0 || nHexChars%2 != 0 {
+		return nil, errors.New("Number of hex characters must be even")
+	}
+	if nHexChars > b.limit-b.position {
+		return nil, ErrBufferEmpty
+	}
+	data := make([]byte, nHexChars/2)
+	if _, err := hex.Decode(data, b.data[b.position:b.position+nHexChars]); err != nil {
+		return nil, err
+	}
+	b.Next(nHexChars)
+	return data, nil
+}
@@ -0,0 +1,52 @@
+package altdata
+
+import "testing"
+
+func TestHexRoundTrip(t *testing.T) {
+	data := []byte{0x00, 0x0f, 0xab, 0xff}
+	b := NewBuffer(16)
+	if n, err := b.WriteHex(data); err != nil || n != 8 {
+		t.Fatal(n, err)
+	}
+	if n, err := b.WriteHexUpper(data); err != nil || n != 8 {
+		t.Fatal(n, err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != "000fabff000FABFF" {
+		t.Fatal(string(b.Bytes()))
+	}
+	for i := 0; i < 2; i++ {
+		if r, err := b.ReadHex(8); err != nil || string(r) != string(data) {
+			t.Fatal(r, err)
+		}
+	}
+	if r, err := b.ReadHex(0); err != nil || len(r) != 0 {
+		t.Fatal(r, err)
+	}
+}
+
+func TestHexInvalid(t *testing.T) {
+	b := Wrap([]byte("0g12abc"))
+	for _, n := range []int{-2, 1, 3, 2, 8} {
+		if _, err := b.ReadHex(n); err == nil || b.Position() != 0 {
+			t.Fatal(n, err, b.Position())
+		}
+	}
+	if _, err := b.ReadHex(8); err != ErrBufferEmpty {
+		t.Fatal(err)
+	}
+	b.Skip(2)
+	if r, err := b.ReadHex(4); err != nil || string(r) != "\x12\xab" {
+		t.Fatal(r, err)
+	}
+}
+
+func TestHexWriteFull(t *testing.T) {
+	b := NewBuffer(3)
+	if _, err := b.WriteHex([]byte{1, 2}); err == nil || b.Position() != 0 {
+		t.Fatal(err)
+	}
+	if _, err := b.WriteHexUpper([]byte{1, 2}); err == nil || b.Position() != 0 {
+		t.Fatal(err)
+	}
+}
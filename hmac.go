@@ -0,0 +1,54 @@
+package altdata
+
+import "crypto/hmac"
+import "crypto/sha256"
+import "errors"
+import "hash"
+
+// Set the hash used by AppendHMAC and VerifyHMAC, nil means SHA-256.
+func (b *Buffer) SetHMACHash(h func() hash.Hash) {
+	b.hmacHash = h
+}
+
+func (b *Buffer) hmac(key []byte, start, end int) []byte {
+	h := b.hmacHash
+	if h == nil {
+		h = sha256.New
+	}
+	mac := hmac.New(h, key)
+	mac.Write(b.data[start:end])
+	return mac.Sum(nil)
+}
+
+// Writes the HMAC of [start:position] computed with key.
+// In append mode the range is [start:limit].
+func (b *Buffer) AppendHMAC(key []byte, start int) error {
+	end := b.writePosition()
+	if start < 0 || start > end {
+		return ErrOutOfRange
+	}
+	mac := b.hmac(key, start, end)
+	p, err := b.reserve(len(mac))
+	if err != nil {
+		return err
+	}
+	copy(p, mac)
+	return nil
+}
+
+// Reads a tag of tagLen bytes and reports whether it matches the first tagLen bytes
+// of the HMAC of [start:position] before the read, compared in constant time.
+func (b *Buffer) VerifyHMAC(key []byte, start, tagLen int) (bool, error) {
+	if start < 0 || start > b.position {
+		return false, ErrOutOfRange
+	}
+	mac := b.hmac(key, start, b.position)
+	if tagLen <= 0 || tagLen > len(mac) {
+		return false, errors.New("Invalid HMAC tag length")
+	}
+	tag, err := b.read(tagLen)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal(tag, mac[:tagLen]), nil
+}
@@ -0,0 +1,87 @@
+package altdata
+
+import "crypto/sha1"
+import "testing"
+
+var hmacKey = []byte("secret")
+
+func signedBuffer() *Buffer {
+	b := NewBuffer(64)
+	b.WriteString("hdr:")
+	b.WriteString("payload")
+	b.AppendHMAC(hmacKey, 4)
+	b.Flip()
+	b.Skip(11)
+	return b
+}
+
+func TestHMACVerify(t *testing.T) {
+	b := signedBuffer()
+	if b.Limit() != 11+32 {
+		t.Fatal(b.Limit())
+	}
+	if ok, err := b.VerifyHMAC(hmacKey, 4, 32); err != nil || !ok || b.Remaining() != 0 {
+		t.Fatal(ok, err)
+	}
+	b = signedBuffer()
+	if ok, err := b.VerifyHMAC(hmacKey, 4, 16); err != nil || !ok || b.Remaining() != 16 {
+		t.Fatal("truncated tag", ok, err)
+	}
+}
+
+func TestHMACTampered(t *testing.T) {
+	for i := 4; i < 11+32; i++ {
+		b := signedBuffer()
+		b.data[i] ^= 1
+		if ok, err := b.VerifyHMAC(hmacKey, 4, 32); err != nil || ok {
+			t.Fatal(i, ok, err)
+		}
+	}
+	b := signedBuffer()
+	if ok, _ := b.VerifyHMAC([]byte("wrong"), 4, 32); ok {
+		t.Fatal("wrong key should not verify")
+	}
+	b = signedBuffer()
+	if ok, _ := b.VerifyHMAC(hmacKey, 0, 32); ok {
+		t.Fatal("different range should not verify")
+	}
+}
+
+func TestHMACErrors(t *testing.T) {
+	b := signedBuffer()
+	for _, n := range []int{0, -1, 33} {
+		if _, err := b.VerifyHMAC(hmacKey, 4, n); err == nil || b.Position() != 11 {
+			t.Fatal(n, err)
+		}
+	}
+	if _, err := b.VerifyHMAC(hmacKey, 12, 32); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+	b.Truncate(20)
+	if _, err := b.VerifyHMAC(hmacKey, 4, 32); err == nil || b.Position() != 11 {
+		t.Fatal("truncated tag should fail", err)
+	}
+	f := NewBuffer(38)
+	f.WriteString("payload")
+	if err := f.AppendHMAC(hmacKey, 0); err == nil || f.Position() != 7 {
+		t.Fatal("AppendHMAC should fail without space", err)
+	}
+	if err := f.AppendHMAC(hmacKey, 8); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+}
+
+func TestHMACHash(t *testing.T) {
+	b := NewBuffer(64)
+	b.SetHMACHash(sha1.New)
+	b.WriteString("payload")
+	b.AppendHMAC(hmacKey, 0)
+	if b.Position() != 7+20 {
+		t.Fatal(b.Position())
+	}
+	b.Flip()
+	b.Skip(7)
+	if ok, err := b.VerifyHMAC(hmacKey, 0, 20); err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+}
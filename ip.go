@@ -0,0 +1,45 @@
+package altdata
+
+import "errors"
+import "net"
+
+// Writes ip in network byte order, the byte order of the Buffer is NOT used.
+// IPv4 addresses, including IPv4-mapped IPv6 addresses, are written as 4 bytes,
+// other addresses as 16 bytes.
+func (b *Buffer) WriteIP(ip net.IP) error {
+	if v4 := ip.To4(); v4 != nil {
+		return b.writeIP(v4)
+	}
+	return b.WriteIP16(ip)
+}
+
+// Writes ip as 16 bytes in network byte order, IPv4 addresses as IPv4-mapped.
+func (b *Buffer) WriteIP16(ip net.IP) error {
+	v6 := ip.To16()
+	if v6 == nil {
+		return errors.New("Invalid IP address")
+	}
+	return b.writeIP(v6)
+}
+
+func (b *Buffer) writeIP(ip net.IP) error {
+	p, err := b.reserve(len(ip))
+	if err != nil {
+		return err
+	}
+	copy(p, ip)
+	return nil
+}
+
+// Reads an IP address of size 4 or 16 bytes in network byte order.
+// Returns an error and does not move if fewer than size bytes remain.
+func (b *Buffer) ReadIP(size int) (net.IP, error) {
+	if size != net.IPv4len && size != net.IPv6len {
+		return nil, errors.New("IP address size must be 4 or 16")
+	}
+	p, err := b.read(size)
+	if err != nil {
+		return nil, err
+	}
+	return append(net.IP{}, p...), nil
+}
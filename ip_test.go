@@ -0,0 +1,68 @@
+package altdata
+
+import "net"
+import "testing"
+
+func TestIPv4Mapped(t *testing.T) {
+	for _, s := range []string{"192.168.1.2", "::ffff:192.168.1.2"} {
+		b := NewBuffer(16)
+		if err := b.WriteIP(net.ParseIP(s)); err != nil {
+			t.Fatal(err)
+		}
+		b.Flip()
+		if string(b.Bytes()) != "\xc0\xa8\x01\x02" {
+			t.Fatalf("%s: % x", s, b.Bytes())
+		}
+		if ip, err := b.ReadIP(4); err != nil || !ip.Equal(net.IPv4(192, 168, 1, 2)) {
+			t.Fatal(ip, err)
+		}
+	}
+}
+
+func TestIPv6(t *testing.T) {
+	ip := net.ParseIP("2001:db8::1")
+	for _, order := range orders {
+		b := NewBufferWithOrder(16, order)
+		if err := b.WriteIP(ip); err != nil || b.Position() != 16 {
+			t.Fatal(err)
+		}
+		b.Flip()
+		if b.Bytes()[0] != 0x20 || b.Bytes()[1] != 0x01 || b.Bytes()[15] != 1 {
+			t.Fatalf("% x", b.Bytes())
+		}
+		if r, err := b.ReadIP(16); err != nil || !r.Equal(ip) {
+			t.Fatal(r, err)
+		}
+	}
+}
+
+func TestIP16(t *testing.T) {
+	b := NewBuffer(16)
+	if err := b.WriteIP16(net.IPv4(10, 0, 0, 1).To4()); err != nil {
+		t.Fatal(err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != "\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\xff\xff\x0a\x00\x00\x01" {
+		t.Fatalf("% x", b.Bytes())
+	}
+}
+
+func TestIPInvalid(t *testing.T) {
+	b := NewBuffer(16)
+	for _, ip := range []net.IP{nil, {1, 2, 3}} {
+		if err := b.WriteIP(ip); err == nil || b.Position() != 0 {
+			t.Fatal(ip, err)
+		}
+	}
+	if err := NewBuffer(3).WriteIP(net.IPv4(1, 2, 3, 4)); err == nil {
+		t.Fatal("WriteIP should fail in 3 bytes")
+	}
+	r := Wrap(make([]byte, 16))
+	if _, err := r.ReadIP(8); err == nil || r.Position() != 0 {
+		t.Fatal("ReadIP(8) should fail", err)
+	}
+	r = Wrap(make([]byte, 3))
+	if _, err := r.ReadIP(4); err == nil || r.Position() != 0 {
+		t.Fatal("ReadIP(4) should fail with 3 bytes", err)
+	}
+}
@@ -0,0 +1,36 @@
+package altdata
+
+import "iter"
+
+// Returns an iterator over the offsets relative to the position and values of
+// the remaining bytes. DOES NOT move the position.
+// Changing the buffer during iteration is undefined.
+func (b *Buffer) All() iter.Seq2[int, byte] {
+	return func(yield func(int, byte) bool) {
+		for i, c := range b.data[b.position:b.limit] {
+			if !yield(i, c) {
+				return
+			}
+		}
+	}
+}
+
+// Returns an iterator over the remaining bytes in pieces of n bytes, where the
+// last piece is shorter if Remaining is not a multiple of n. The pieces alias
+// the buffer. DOES NOT move the position.
+// Changing the buffer during iteration is undefined. Will panic if n <= 0!
+func (b *Buffer) Words(n int) iter.Seq[[]byte] {
+	if n <= 0 {
+		panic("Word size must be positive!")
+	}
+	return func(yield func([]byte) bool) {
+		data := b.data[b.position:b.limit]
+		for len(data) > 0 {
+			end := min(n, len(data))
+			if !yield(data[:end:end]) {
+				return
+			}
+			data = data[end:]
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package altdata
+
+import "bytes"
+import "fmt"
+
+// Reads len(magic) bytes and checks that they equal magic.
+// Returns an error and does not move if they differ or fewer bytes remain,
+// so the actual bytes can still be read.
+func (b *Buffer) ExpectMagic(magic []byte) error {
+	if err := b.checkMode(ModeRead); err != nil {
+		return err
+	}
+	if len(magic) > b.limit-b.position {
+		return ErrBufferEmpty
+	}
+	got := b.data[b.position : b.position+len(magic)]
+	if !bytes.Equal(got, magic) {
+		return fmt.Errorf("Magic mismatch, expected % x got % x", magic, got)
+	}
+	_, err := b.read(len(magic))
+	return err
+}
+
+// Writes magic, returning an error and writing nothing if there is not space enough.
+func (b *Buffer) WriteMagic(magic []byte) error {
+	p, err := b.reserve(len(magic))
+	if err != nil {
+		return err
+	}
+	copy(p, magic)
+	return nil
+}
@@ -0,0 +1,48 @@
+package altdata
+
+import "testing"
+
+func TestMagic(t *testing.T) {
+	b := NewBuffer(8)
+	if err := b.WriteMagic([]byte("ALT1")); err != nil {
+		t.Fatal(err)
+	}
+	b.WriteByte(9)
+	b.Flip()
+	if err := b.ExpectMagic([]byte("ALT2")); err == nil || b.Position() != 0 {
+		t.Fatal("mismatch should not move", err)
+	}
+	if err := b.ExpectMagic([]byte("ALT1")); err != nil || b.Position() != 4 {
+		t.Fatal(err)
+	}
+	if err := b.ExpectMagic([]byte("ALT1")); err != ErrBufferEmpty || b.Position() != 4 {
+		t.Fatal(err)
+	}
+	if err := b.ExpectMagic(nil); err != nil || b.Position() != 4 {
+		t.Fatal(err)
+	}
+	if c, _ := b.ReadByte(); c != 9 {
+		t.Fatal(c)
+	}
+}
+
+func TestWriteMagicFull(t *testing.T) {
+	s := NewBuffer(2)
+	if err := s.WriteMagic([]byte("abc")); err == nil || s.Position() != 0 {
+		t.Fatal(err)
+	}
+}
+
+func TestExpectMagicStrictMode(t *testing.T) {
+	b := NewBuffer(8)
+	b.SetStrict(true)
+	b.WriteMagic([]byte("ALT1"))
+	b.SetManual(0, 4)
+	if err := b.ExpectMagic([]byte("ALT1")); err == nil || b.Position() != 0 {
+		t.Fatal("ExpectMagic in write mode should fail", err)
+	}
+	b.SetMode(ModeRead)
+	if err := b.ExpectMagic([]byte("ALT1")); err != nil || b.Position() != 4 {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,38 @@
+package altdata
+
+import "fmt"
+
+// Whether a Buffer is being written or read, see Mode.
+type Mode int
+
+const (
+	ModeWrite Mode = iota // Set by NewBuffer, Clear and Compact.
+	ModeRead              // Set by Wrap, Rewrap and Flip.
+)
+
+func (m Mode) String() string {
+	if m == ModeRead {
+		return "read"
+	}
+	return "write"
+}
+
+// Returns whether the buffer is being written or read. In strict mode, see
+// SetStrict, read methods return an error in write mode and write methods in
+// read mode. Append mode allows both. Other modes only track the mode.
+func (b *Buffer) Mode() Mode {
+	return b.mode
+}
+
+// Sets the mode manually, for instance after SetManual or Restore in strict mode.
+func (b *Buffer) SetMode(mode Mode) {
+	b.mode = mode
+}
+
+// Returns an error naming the method if the buffer is strict and not in mode.
+func (b *Buffer) checkMode(mode Mode) error {
+	if !b.strict || b.append || b.mode == mode {
+		return nil
+	}
+	return fmt.Errorf("%s called in %s mode", callerMethod(), b.mode)
+}
@@ -0,0 +1,176 @@
+package altdata
+
+import "fmt"
+import "io"
+import "strings"
+import "testing"
+
+func TestModeTracking(t *testing.T) {
+	b := NewBuffer(8)
+	if b.Mode() != ModeWrite {
+		t.Fatal(b.Mode())
+	}
+	b.Flip()
+	if b.Mode() != ModeRead {
+		t.Fatal(b.Mode())
+	}
+	b.Compact()
+	if b.Mode() != ModeWrite {
+		t.Fatal(b.Mode())
+	}
+	if Wrap([]byte("a")).Mode() != ModeRead || ModeRead.String() != "read" || ModeWrite.String() != "write" {
+		t.Fatal("Wrap should be in read mode")
+	}
+}
+
+func TestStrictReadInWriteMode(t *testing.T) {
+	reads := map[string]func(*Buffer) error{
+		"ReadUint32":          func(b *Buffer) error { _, err := b.ReadUint32(); return err },
+		"ReadByte":            func(b *Buffer) error { _, err := b.ReadByte(); return err },
+		"Read":                func(b *Buffer) error { _, err := b.Read(make([]byte, 2)); return err },
+		"ReadFull":            func(b *Buffer) error { return b.ReadFull(make([]byte, 2)) },
+		"ReadInto":            func(b *Buffer) error { _, err := b.ReadInto(make([]byte, 2)); return err },
+		"TryNext":             func(b *Buffer) error { _, err := b.TryNext(2); return err },
+		"TryNextString":       func(b *Buffer) error { _, err := b.TryNextString(2); return err },
+		"Discard":             func(b *Buffer) error { _, err := b.Discard(2); return err },
+		"ReadUntil":           func(b *Buffer) error { _, err := b.ReadUntil('b'); return err },
+		"ReadUntilExclusive":  func(b *Buffer) error { _, err := b.ReadUntilExclusive('b'); return err },
+		"ReadLine":            func(b *Buffer) error { _, err := b.ReadLine(); return err },
+		"ReadUint16Slice":     func(b *Buffer) error { _, err := b.ReadUint16Slice(1); return err },
+		"ReadFloat64Slice":    func(b *Buffer) error { _, err := b.ReadFloat64Slice(0); return err },
+		"ReadUTF16":           func(b *Buffer) error { _, err := b.ReadUTF16(1); return err },
+		"ReadHex":             func(b *Buffer) error { _, err := b.ReadHex(2); return err },
+		"ReadFrame":           func(b *Buffer) error { _, err := b.ReadFrame(); return err },
+		"ReadCString":         func(b *Buffer) error { _, err := b.ReadCString(); return err },
+		"ReadRune":            func(b *Buffer) error { _, _, err := b.ReadRune(); return err },
+		"PeekByte":            func(b *Buffer) error { _, err := b.PeekByte(); return err },
+		"PeekUint16":          func(b *Buffer) error { _, err := b.PeekUint16(); return err },
+		"PeekUint32":          func(b *Buffer) error { _, err := b.PeekUint32(); return err },
+		"Read7BitEncodedInt":  func(b *Buffer) error { _, err := b.Read7BitEncodedInt(); return err },
+		"ReadDotNetString":    func(b *Buffer) error { _, err := b.ReadDotNetString(); return err },
+		"ReadLString":         func(b *Buffer) error { _, err := b.ReadLString(); return err },
+		"ReadPascalString":    func(b *Buffer) error { _, err := b.ReadPascalString(); return err },
+		"ReadUvarint":         func(b *Buffer) error { _, _, err := b.ReadUvarint(); return err },
+		"ReadVarBytes":        func(b *Buffer) error { _, err := b.ReadVarBytes(); return err },
+		"SplitLengthPrefixed": func(b *Buffer) error { _, err := b.SplitLengthPrefixed(1); return err },
+		"SplitFixed":          func(b *Buffer) error { _, err := b.SplitFixed(1); return err },
+		"ExpectMagic":         func(b *Buffer) error { return b.ExpectMagic([]byte("a")) },
+	}
+	for name, read := range reads {
+		b := NewBuffer(8)
+		b.SetStrict(true)
+		b.WriteString("\x01a\x00b\n")
+		b.SetManual(0, 5)
+		var err error
+		if p := catchPanic(func() { err = read(b) }); p != nil {
+			t.Fatal(name, "panicked:", p)
+		}
+		if err == nil || !strings.Contains(err.Error(), "called in write mode") || b.Position() != 0 {
+			t.Fatal(name, err, b.Position())
+		}
+	}
+}
+
+func TestStrictWriteInReadMode(t *testing.T) {
+	writes := map[string]func(*Buffer) error{
+		"WriteByte":   func(b *Buffer) error { return b.WriteByte(1) },
+		"WriteUint32": func(b *Buffer) error { return b.WriteUint32(1) },
+		"Write":       func(b *Buffer) error { _, err := b.Write([]byte("x")); return err },
+		"WriteString": func(b *Buffer) error { _, err := b.WriteString("x"); return err },
+		"Writer":      func(b *Buffer) error { _, err := fmt.Fprint(b.Writer(), "x"); return err },
+	}
+	for name, write := range writes {
+		b := NewBuffer(8)
+		b.SetStrict(true)
+		b.WriteString("ab")
+		b.Flip()
+		if err := write(b); err == nil || !strings.Contains(err.Error(), "called in read mode") {
+			t.Fatal(name, err)
+		}
+		if string(b.Bytes()) != "ab" {
+			t.Fatal(name, "wrote", string(b.Bytes()))
+		}
+	}
+}
+
+func TestStrictModeErrorNamesMethod(t *testing.T) {
+	b := NewBuffer(8)
+	b.SetStrict(true)
+	b.WriteUint32(5)
+	if _, err := b.ReadUint32(); err == nil || !strings.Contains(err.Error(), "Buffer.ReadUint32 called in write mode") {
+		t.Fatal(err)
+	}
+	if _, err := b.ReadUint16Slice(1); err == nil || !strings.Contains(err.Error(), "Buffer.ReadUint16Slice") {
+		t.Fatal(err)
+	}
+	b.Flip()
+	if v, err := b.ReadUint32(); v != 5 || err != nil {
+		t.Fatal(v, err)
+	}
+	if err := b.WriteByte(1); err == nil || !strings.Contains(err.Error(), "Buffer.WriteByte called in read mode") {
+		t.Fatal(err)
+	}
+	// Next has no error to return, so it panics.
+	b.Clear()
+	if msg := fmt.Sprint(catchPanic(func() { b.Next(1) })); !strings.Contains(msg, "Buffer.Next called in write mode") {
+		t.Fatal(msg)
+	}
+}
+
+func TestStrictModeEOFStillReported(t *testing.T) {
+	b := Wrap([]byte("a"))
+	b.SetStrict(true)
+	if err := b.ReadFull(make([]byte, 2)); err != io.ErrUnexpectedEOF || b.Position() != 0 {
+		t.Fatal(err)
+	}
+	if n, err := b.ReadInto(make([]byte, 2)); n != 1 || err != io.ErrUnexpectedEOF {
+		t.Fatal(n, err)
+	}
+	if n, err := b.ReadInto(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+	b.Rewind()
+	if line, err := b.ReadLine(); line != "a" || err != io.EOF {
+		t.Fatal(line, err)
+	}
+}
+
+func TestModeNotCheckedWithoutStrict(t *testing.T) {
+	b := NewBuffer(4)
+	b.WriteUint16(3)
+	b.SetManual(0, 2)
+	if v, err := b.ReadUint16(); v != 3 || err != nil {
+		t.Fatal(v, err)
+	}
+	a := NewAppendBuffer(4)
+	a.SetStrict(true)
+	a.WriteUint16(3)
+	if v, err := a.ReadUint16(); v != 3 || err != nil {
+		t.Fatal("append mode allows both", err)
+	}
+	fmt.Fprint(a.Writer(), "x")
+	if a.Remaining() != 1 {
+		t.Fatal(a.Remaining())
+	}
+}
+
+func TestStrictEditInReadMode(t *testing.T) {
+	b := NewBuffer(8)
+	b.SetStrict(true)
+	b.WriteString("abc")
+	b.Flip()
+	b.Skip(1)
+	if err := b.Prepend([]byte("x")); err != nil || string(b.Bytes()) != "xbc" {
+		t.Fatal(err, string(b.Bytes()))
+	}
+	if err := b.Insert([]byte("y"), 0); err != nil || string(b.Bytes()) != "xbc" || b.Position() != 2 {
+		t.Fatal(err, string(b.Bytes()), b.Position())
+	}
+	if err := b.Delete(2, 1); err != nil || string(b.Bytes()) != "bc" {
+		t.Fatal(err, string(b.Bytes()))
+	}
+	b.position = -1
+	if catchPanic(func() { b.Delete(0, 1) }) == nil || catchPanic(func() { b.Insert(nil, 0) }) == nil {
+		t.Fatal("a corrupted state should panic in strict mode")
+	}
+}
@@ -0,0 +1,397 @@
+package altdata
+
+import "math"
+
+// Typed accessors for fixed size integers and floats. Reads return an error without
+// moving the position if not enough bytes remain, writes return an error
+// if there is not space enough and the Buffer is not growable.
+
+// Reads an int8.
+func (b *Buffer) ReadInt8() (int8, error) {
+	p, err := b.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return int8(p[0]), nil
+}
+
+// Reads a uint8.
+func (b *Buffer) ReadUint8() (uint8, error) {
+	p, err := b.read(1)
+	if err != nil {
+		return 0, err
+	}
+	return p[0], nil
+}
+
+// Reads an int16 using the byte order.
+func (b *Buffer) ReadInt16() (int16, error) {
+	p, err := b.read(2)
+	if err != nil {
+		return 0, err
+	}
+	return int16(b.order.Uint16(p)), nil
+}
+
+// Reads a uint16 using the byte order.
+func (b *Buffer) ReadUint16() (uint16, error) {
+	p, err := b.read(2)
+	if err != nil {
+		return 0, err
+	}
+	return b.order.Uint16(p), nil
+}
+
+// Reads an int32 using the byte order.
+func (b *Buffer) ReadInt32() (int32, error) {
+	p, err := b.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return int32(b.order.Uint32(p)), nil
+}
+
+// Reads a uint32 using the byte order.
+func (b *Buffer) ReadUint32() (uint32, error) {
+	p, err := b.read(4)
+	if err != nil {
+		return 0, err
+	}
+	return b.order.Uint32(p), nil
+}
+
+// Reads an int64 using the byte order.
+func (b *Buffer) ReadInt64() (int64, error) {
+	p, err := b.read(8)
+	if err != nil {
+		return 0, err
+	}
+	return int64(b.order.Uint64(p)), nil
+}
+
+// Reads a uint64 using the byte order.
+func (b *Buffer) ReadUint64() (uint64, error) {
+	p, err := b.read(8)
+	if err != nil {
+		return 0, err
+	}
+	return b.order.Uint64(p), nil
+}
+
+// Writes an int8.
+func (b *Buffer) WriteInt8(v int8) error {
+	p, err := b.reserve(1)
+	if err != nil {
+		return err
+	}
+	p[0] = byte(v)
+	return nil
+}
+
+// Writes a uint8.
+func (b *Buffer) WriteUint8(v uint8) error {
+	p, err := b.reserve(1)
+	if err != nil {
+		return err
+	}
+	p[0] = v
+	return nil
+}
+
+// Writes an int16 using the byte order.
+func (b *Buffer) WriteInt16(v int16) error {
+	p, err := b.reserve(2)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint16(p, uint16(v))
+	return nil
+}
+
+// Writes a uint16 using the byte order.
+func (b *Buffer) WriteUint16(v uint16) error {
+	p, err := b.reserve(2)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint16(p, v)
+	return nil
+}
+
+// Writes an int32 using the byte order.
+func (b *Buffer) WriteInt32(v int32) error {
+	p, err := b.reserve(4)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint32(p, uint32(v))
+	return nil
+}
+
+// Writes a uint32 using the byte order.
+func (b *Buffer) WriteUint32(v uint32) error {
+	p, err := b.reserve(4)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint32(p, v)
+	return nil
+}
+
+// Writes an int64 using the byte order.
+func (b *Buffer) WriteInt64(v int64) error {
+	p, err := b.reserve(8)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint64(p, uint64(v))
+	return nil
+}
+
+// Writes a uint64 using the byte order.
+func (b *Buffer) WriteUint64(v uint64) error {
+	p, err := b.reserve(8)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint64(p, v)
+	return nil
+}
+
+// Returns the next uint16 using the byte order, without moving the position.
+func (b *Buffer) PeekUint16() (uint16, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, err
+	}
+	if b.position+2 > b.limit {
+		return 0, ErrBufferEmpty
+	}
+	return b.order.Uint16(b.data[b.position:]), nil
+}
+
+// Returns the next uint32 using the byte order, without moving the position.
+func (b *Buffer) PeekUint32() (uint32, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, err
+	}
+	if b.position+4 > b.limit {
+		return 0, ErrBufferEmpty
+	}
+	return b.order.Uint32(b.data[b.position:]), nil
+}
+
+// Reads a float32 using the byte order.
+func (b *Buffer) ReadFloat32() (float32, error) {
+	v, err := b.ReadUint32()
+	return math.Float32frombits(v), err
+}
+
+// Reads a float64 using the byte order.
+func (b *Buffer) ReadFloat64() (float64, error) {
+	v, err := b.ReadUint64()
+	return math.Float64frombits(v), err
+}
+
+// Writes a float32 using the byte order.
+func (b *Buffer) WriteFloat32(v float32) error {
+	return b.WriteUint32(math.Float32bits(v))
+}
+
+// Writes a float64 using the byte order.
+func (b *Buffer) WriteFloat64(v float64) error {
+	return b.WriteUint64(math.Float64bits(v))
+}
+
+// Returns n bytes of the backing array at the absolute offset, or an error if out of range.
+func (b *Buffer) at(offset, n int) ([]byte, error) {
+	if offset < 0 || offset > len(b.data)-n {
+		return nil, ErrOutOfRange
+	}
+	return b.data[offset : offset+n], nil
+}
+
+// Absolute accessors. Offsets are from the start of the backing array, they
+// DO NOT depend on or move the position and limit.
+
+// Reads a uint16 at the absolute offset using the byte order.
+func (b *Buffer) GetUint16At(offset int) (uint16, error) {
+	p, err := b.at(offset, 2)
+	if err != nil {
+		return 0, err
+	}
+	return b.order.Uint16(p), nil
+}
+
+// Writes a uint16 at the absolute offset using the byte order.
+func (b *Buffer) PutUint16At(offset int, v uint16) error {
+	p, err := b.at(offset, 2)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint16(p, v)
+	return nil
+}
+
+// Reads an int16 at the absolute offset using the byte order.
+func (b *Buffer) GetInt16At(offset int) (int16, error) {
+	v, err := b.GetUint16At(offset)
+	return int16(v), err
+}
+
+// Writes an int16 at the absolute offset using the byte order.
+func (b *Buffer) PutInt16At(offset int, v int16) error {
+	return b.PutUint16At(offset, uint16(v))
+}
+
+// Reads a uint32 at the absolute offset using the byte order.
+func (b *Buffer) GetUint32At(offset int) (uint32, error) {
+	p, err := b.at(offset, 4)
+	if err != nil {
+		return 0, err
+	}
+	return b.order.Uint32(p), nil
+}
+
+// Writes a uint32 at the absolute offset using the byte order.
+func (b *Buffer) PutUint32At(offset int, v uint32) error {
+	p, err := b.at(offset, 4)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint32(p, v)
+	return nil
+}
+
+// Reads an int32 at the absolute offset using the byte order.
+func (b *Buffer) GetInt32At(offset int) (int32, error) {
+	v, err := b.GetUint32At(offset)
+	return int32(v), err
+}
+
+// Writes an int32 at the absolute offset using the byte order.
+func (b *Buffer) PutInt32At(offset int, v int32) error {
+	return b.PutUint32At(offset, uint32(v))
+}
+
+// Reads a uint64 at the absolute offset using the byte order.
+func (b *Buffer) GetUint64At(offset int) (uint64, error) {
+	p, err := b.at(offset, 8)
+	if err != nil {
+		return 0, err
+	}
+	return b.order.Uint64(p), nil
+}
+
+// Writes a uint64 at the absolute offset using the byte order.
+func (b *Buffer) PutUint64At(offset int, v uint64) error {
+	p, err := b.at(offset, 8)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint64(p, v)
+	return nil
+}
+
+// Reads an int64 at the absolute offset using the byte order.
+func (b *Buffer) GetInt64At(offset int) (int64, error) {
+	v, err := b.GetUint64At(offset)
+	return int64(v), err
+}
+
+// Writes an int64 at the absolute offset using the byte order.
+func (b *Buffer) PutInt64At(offset int, v int64) error {
+	return b.PutUint64At(offset, uint64(v))
+}
+
+// Reads a float32 at the absolute offset using the byte order.
+func (b *Buffer) GetFloat32At(offset int) (float32, error) {
+	v, err := b.GetUint32At(offset)
+	return math.Float32frombits(v), err
+}
+
+// Writes a float32 at the absolute offset using the byte order.
+func (b *Buffer) PutFloat32At(offset int, v float32) error {
+	return b.PutUint32At(offset, math.Float32bits(v))
+}
+
+// Reads a float64 at the absolute offset using the byte order.
+func (b *Buffer) GetFloat64At(offset int) (float64, error) {
+	v, err := b.GetUint64At(offset)
+	return math.Float64frombits(v), err
+}
+
+// Writes a float64 at the absolute offset using the byte order.
+func (b *Buffer) PutFloat64At(offset int, v float64) error {
+	return b.PutUint64At(offset, math.Float64bits(v))
+}
+
+// Backpatch a uint16, same as PutUint16At.
+func (b *Buffer) PatchUint16(offset int, v uint16) error {
+	return b.PutUint16At(offset, v)
+}
+
+// Backpatch a uint32, same as PutUint32At.
+func (b *Buffer) PatchUint32(offset int, v uint32) error {
+	return b.PutUint32At(offset, v)
+}
+
+// Backpatch a uint64, same as PutUint64At.
+func (b *Buffer) PatchUint64(offset int, v uint64) error {
+	return b.PutUint64At(offset, v)
+}
+
+// Bulk accessors, copying directly when the byte order matches the host.
+
+// Reads n uint16 values using the byte order.
+func (b *Buffer) ReadUint16Slice(n int) ([]uint16, error) {
+	return ReadSlice[uint16](b, n)
+}
+
+// Writes all uint16 values of s using the byte order.
+func (b *Buffer) WriteUint16Slice(s []uint16) error {
+	return WriteSlice(b, s)
+}
+
+// Reads n int32 values using the byte order.
+func (b *Buffer) ReadInt32Slice(n int) ([]int32, error) {
+	return ReadSlice[int32](b, n)
+}
+
+// Writes all int32 values of s using the byte order.
+func (b *Buffer) WriteInt32Slice(s []int32) error {
+	return WriteSlice(b, s)
+}
+
+// Reads n float32 values using the byte order.
+func (b *Buffer) ReadFloat32Slice(n int) ([]float32, error) {
+	return ReadSlice[float32](b, n)
+}
+
+// Writes all float32 values of s using the byte order.
+func (b *Buffer) WriteFloat32Slice(s []float32) error {
+	return WriteSlice(b, s)
+}
+
+// Reads n float64 values using the byte order.
+func (b *Buffer) ReadFloat64Slice(n int) ([]float64, error) {
+	return ReadSlice[float64](b, n)
+}
+
+// Writes all float64 values of s using the byte order.
+func (b *Buffer) WriteFloat64Slice(s []float64) error {
+	return WriteSlice(b, s)
+}
+
+// Writes v as a single byte, 1 for true and 0 for false.
+func (b *Buffer) WriteBool(v bool) error {
+	if v {
+		return b.WriteUint8(1)
+	}
+	return b.WriteUint8(0)
+}
+
+// Reads a single byte as a bool. Any non-zero byte is true, not only 1.
+func (b *Buffer) ReadBool() (bool, error) {
+	v, err := b.ReadUint8()
+	return v != 0, err
+}
@@ -0,0 +1,176 @@
+package altdata
+
+import "encoding/binary"
+import "math"
+import "testing"
+
+var orders = []binary.ByteOrder{binary.LittleEndian, binary.BigEndian}
+
+func TestFloat32RoundTrip(t *testing.T) {
+	values := []float32{0, float32(math.Copysign(0, -1)), 1.5, -math.MaxFloat32, math.SmallestNonzeroFloat32,
+		float32(math.Inf(1)), float32(math.Inf(-1)), float32(math.NaN())}
+	for _, order := range orders {
+		b := NewBufferWithOrder(4*len(values), order)
+		for _, v := range values {
+			if err := b.WriteFloat32(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		b.Flip()
+		for _, v := range values {
+			r, err := b.ReadFloat32()
+			if err != nil || math.Float32bits(r) != math.Float32bits(v) {
+				t.Fatal(order, v, r, err)
+			}
+		}
+	}
+}
+
+func TestFloat64RoundTrip(t *testing.T) {
+	values := []float64{0, math.Copysign(0, -1), 1.5, -math.MaxFloat64, math.SmallestNonzeroFloat64,
+		math.Inf(1), math.Inf(-1), math.NaN()}
+	for _, order := range orders {
+		b := NewBufferWithOrder(8*len(values), order)
+		for _, v := range values {
+			if err := b.WriteFloat64(v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		b.Flip()
+		for _, v := range values {
+			r, err := b.ReadFloat64()
+			if err != nil || math.Float64bits(r) != math.Float64bits(v) {
+				t.Fatal(order, v, r, err)
+			}
+		}
+	}
+}
+
+func TestFloatShort(t *testing.T) {
+	b := Wrap([]byte{1, 2, 3})
+	if _, err := b.ReadFloat32(); err == nil || b.Position() != 0 {
+		t.Fatal(err)
+	}
+	if err := b.WriteFloat64(1); err == nil {
+		t.Fatal("WriteFloat64 should fail without space")
+	}
+}
+
+func TestBool(t *testing.T) {
+	b := NewBuffer(2)
+	b.WriteBool(true)
+	b.WriteBool(false)
+	b.Flip()
+	if string(b.Bytes()) != "\x01\x00" {
+		t.Fatalf("% x", b.Bytes())
+	}
+	if v, err := b.ReadBool(); err != nil || !v {
+		t.Fatal(v, err)
+	}
+	if v, err := b.ReadBool(); err != nil || v {
+		t.Fatal(v, err)
+	}
+	if _, err := b.ReadBool(); err == nil {
+		t.Fatal("ReadBool of an empty buffer should fail")
+	}
+}
+
+func TestBoolNonCanonical(t *testing.T) {
+	for _, c := range []byte{2, 0x80, 0xff} {
+		if v, err := Wrap([]byte{c}).ReadBool(); err != nil || !v {
+			t.Fatal(c, v, err)
+		}
+	}
+}
+
+func TestGetPutAt(t *testing.T) {
+	for _, order := range orders {
+		b := NewBufferWithOrder(40, order)
+		b.WriteString("abc")
+		pos, limit := b.Position(), b.Limit()
+		// Offsets before and beyond the position.
+		if b.PutUint16At(0, 0x0102) != nil || b.PutUint32At(4, 0x03040506) != nil ||
+			b.PutUint64At(8, 0x0708090a0b0c0d0e) != nil || b.PutInt16At(16, -2) != nil ||
+			b.PutInt32At(18, -3) != nil || b.PutInt64At(22, -4) != nil || b.PutFloat32At(30, 1.5) != nil {
+			t.Fatal("put failed")
+		}
+		if b.Position() != pos || b.Limit() != limit {
+			t.Fatal("put moved", b.Position(), b.Limit())
+		}
+		var want [2]byte
+		order.PutUint16(want[:], 0x0102)
+		if b.data[0] != want[0] || b.data[1] != want[1] {
+			t.Fatalf("%v: % x", order, b.data[:2])
+		}
+		if v, err := b.GetUint16At(0); err != nil || v != 0x0102 {
+			t.Fatal(v, err)
+		}
+		if v, _ := b.GetUint32At(4); v != 0x03040506 || order.Uint32(b.data[4:]) != v {
+			t.Fatal(v)
+		}
+		if v, _ := b.GetUint64At(8); v != 0x0708090a0b0c0d0e {
+			t.Fatal(v)
+		}
+		if v, _ := b.GetInt16At(16); v != -2 {
+			t.Fatal(v)
+		}
+		if v, _ := b.GetInt32At(18); v != -3 {
+			t.Fatal(v)
+		}
+		if v, _ := b.GetInt64At(22); v != -4 {
+			t.Fatal(v)
+		}
+		if v, _ := b.GetFloat32At(30); v != 1.5 {
+			t.Fatal(v)
+		}
+		b.PutFloat64At(32, math.Pi)
+		if v, _ := b.GetFloat64At(32); v != math.Pi {
+			t.Fatal(v)
+		}
+		if b.Position() != pos || b.Limit() != limit {
+			t.Fatal("get moved", b.Position(), b.Limit())
+		}
+	}
+}
+
+func TestGetPutAtOutOfRange(t *testing.T) {
+	b := NewBuffer(8)
+	for _, offset := range []int{-1, 7, 8, math.MaxInt} {
+		if err := b.PutUint16At(offset, 1); err != ErrOutOfRange {
+			t.Fatal(offset, err)
+		}
+		if _, err := b.GetUint16At(offset); err != ErrOutOfRange {
+			t.Fatal(offset, err)
+		}
+	}
+	if err := b.PutUint64At(1, 1); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+	if _, err := b.GetUint32At(5); err != ErrOutOfRange {
+		t.Fatal(err)
+	}
+	if err := b.PutUint64At(0, 1); err != nil {
+		t.Fatal("full range should work", err)
+	}
+	if err := b.PatchUint32(4, 2); err != nil || b.data[4] != 2 {
+		t.Fatal(err)
+	}
+	if b.Position() != 0 {
+		t.Fatal(b.Position())
+	}
+}
+
+func TestNewBufferBELE(t *testing.T) {
+	be := NewBufferBE(4)
+	be.WriteUint16(0x0102)
+	le := NewBufferLE(4)
+	le.WriteUint16(0x0102)
+	be.Flip()
+	le.Flip()
+	if string(be.Bytes()) != "\x01\x02" || be.Order() != binary.BigEndian || be.Capacity() != 4 {
+		t.Fatalf("% x", be.Bytes())
+	}
+	if string(le.Bytes()) != "\x02\x01" || le.Order() != binary.LittleEndian || le.Capacity() != 4 {
+		t.Fatalf("% x", le.Bytes())
+	}
+}
@@ -0,0 +1,395 @@
+package altdata
+
+import "encoding/binary"
+import "errors"
+import "fmt"
+import "reflect"
+import "strconv"
+import "strings"
+
+// Options from an altdata struct tag.
+type packTag struct {
+	wire      string
+	order     binary.ByteOrder
+	skip      int
+	prefix    LengthPrefix
+	hasPrefix bool
+	ignore    bool
+}
+
+// Parses a comma separated altdata tag like "uint32,be" or "skip:3".
+func parsePackTag(tag string) (packTag, error) {
+	var t packTag
+	if tag == "-" {
+		t.ignore = true
+		return t, nil
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), ":")
+		switch key {
+		case "":
+		case "be":
+			t.order = binary.BigEndian
+		case "le":
+			t.order = binary.LittleEndian
+		case "skip":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return t, fmt.Errorf("Invalid skip %q in altdata tag", value)
+			}
+			t.skip = n
+		case "prefix":
+			prefixes := map[string]LengthPrefix{"uint8": PrefixUint8, "uint16": PrefixUint16,
+				"uint32": PrefixUint32, "varint": PrefixVarint}
+			prefix, ok := prefixes[value]
+			if !ok {
+				return t, fmt.Errorf("Invalid prefix %q in altdata tag", value)
+			}
+			t.prefix = prefix
+			t.hasPrefix = true
+		case "int8", "uint8", "int16", "uint16", "int32", "uint32", "int64", "uint64",
+			"float32", "float64", "bool", "lstring", "cstring", "pascal":
+			t.wire = key
+		default:
+			return t, fmt.Errorf("Unknown option %q in altdata tag", opt)
+		}
+	}
+	return t, nil
+}
+
+// Returns the wire type used for a kind without a type in its tag, or "" if there is none.
+func defaultWire(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int8, reflect.Uint8, reflect.Int16, reflect.Uint16, reflect.Int32, reflect.Uint32,
+		reflect.Int64, reflect.Uint64, reflect.Float32, reflect.Float64, reflect.Bool:
+		return kind.String()
+	case reflect.String:
+		return "lstring"
+	}
+	return ""
+}
+
+// Writes the exported fields of the struct v, or pointer to struct, in order.
+// Fields are written by their type using the byte order and length prefix of
+// the buffer, which the altdata struct tag can change with comma separated options:
+//
+//	"uint32"        wire type of a number field, int8 to uint64, float32, float64 or bool
+//	"be", "le"      byte order of the field, also used for nested structs and elements
+//	"skip:3"        the field is 3 bytes of padding, written as zeros and skipped by Unpack
+//	"lstring"       string with a length prefix, default for strings
+//	"cstring"       zero terminated string
+//	"pascal"        string with a uint8 length prefix
+//	"prefix:uint8"  length prefix of strings and slices, uint8, uint16, uint32 or varint
+//	"-"             the field is ignored
+//
+// Arrays are written element by element and slices with a length prefix first.
+// int and uint fields need a wire type, as they have no fixed size.
+// Returns an error and writes nothing if a field can not be written.
+func (b *Buffer) Pack(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return errors.New("Pack needs a struct or pointer to struct")
+	}
+	start := b.writePosition()
+	order, prefix := b.order, b.prefix
+	err := b.packStruct(rv)
+	b.order, b.prefix = order, prefix
+	if err != nil {
+		b.setWritePosition(start)
+	}
+	return err
+}
+
+// Reads the exported fields of the struct v points to, in order.
+// Uses the same altdata struct tags as Pack.
+// Returns an error and does not move if a field can not be read,
+// in which case v may be partially filled.
+func (b *Buffer) Unpack(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("Unpack needs a pointer to struct")
+	}
+	start := b.position
+	order, prefix := b.order, b.prefix
+	err := b.unpackStruct(rv.Elem())
+	b.order, b.prefix = order, prefix
+	if err != nil {
+		b.position = start
+	}
+	return err
+}
+
+func (b *Buffer) packStruct(rv reflect.Value) error {
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		tag, err := parsePackTag(field.Tag.Get("altdata"))
+		if err != nil {
+			return fmt.Errorf("Field %s: %w", field.Name, err)
+		}
+		if tag.skip > 0 {
+			if _, err := b.Fill(0, tag.skip); err != nil {
+				return fmt.Errorf("Field %s: %w", field.Name, err)
+			}
+			continue
+		}
+		if tag.ignore || !field.IsExported() {
+			continue
+		}
+		if err := b.packValue(rv.Field(i), tag); err != nil {
+			return fmt.Errorf("Field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func (b *Buffer) unpackStruct(rv reflect.Value) error {
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		tag, err := parsePackTag(field.Tag.Get("altdata"))
+		if err != nil {
+			return fmt.Errorf("Field %s: %w", field.Name, err)
+		}
+		if tag.skip > 0 {
+			if err := b.Skip(tag.skip); err != nil {
+				return fmt.Errorf("Field %s: %w", field.Name, err)
+			}
+			continue
+		}
+		if tag.ignore || !field.IsExported() {
+			continue
+		}
+		if err := b.unpackValue(rv.Field(i), tag); err != nil {
+			return fmt.Errorf("Field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func (b *Buffer) packValue(v reflect.Value, tag packTag) error {
+	order, prefix := b.order, b.prefix
+	defer func() { b.order, b.prefix = order, prefix }()
+	if tag.order != nil {
+		b.order = tag.order
+	}
+	if tag.hasPrefix {
+		b.prefix = tag.prefix
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return b.packStruct(v)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := b.packValue(v.Index(i), tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if err := b.writeLength(v.Len()); err != nil {
+			return err
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && tag.wire == "" {
+			_, err := b.Write(v.Bytes())
+			return err
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := b.packValue(v.Index(i), tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	wire := tag.wire
+	if wire == "" {
+		wire = defaultWire(v.Kind())
+	}
+	switch wire {
+	case "lstring", "cstring", "pascal":
+		if v.Kind() != reflect.String {
+			break
+		}
+		switch wire {
+		case "cstring":
+			return b.WriteCString(v.String())
+		case "pascal":
+			return b.WritePascalString(v.String())
+		}
+		return b.WriteLString(v.String())
+	case "bool":
+		if v.Kind() != reflect.Bool {
+			break
+		}
+		return b.WriteBool(v.Bool())
+	case "float32", "float64":
+		if !v.CanFloat() {
+			break
+		}
+		if wire == "float32" {
+			return b.WriteFloat32(float32(v.Float()))
+		}
+		return b.WriteFloat64(v.Float())
+	case "int8", "uint8", "int16", "uint16", "int32", "uint32", "int64", "uint64":
+		var x uint64
+		if v.CanInt() {
+			x = uint64(v.Int())
+		} else if v.CanUint() {
+			x = v.Uint()
+		} else {
+			break
+		}
+		switch wire {
+		case "int8", "uint8":
+			return b.WriteUint8(uint8(x))
+		case "int16", "uint16":
+			return b.WriteUint16(uint16(x))
+		case "int32", "uint32":
+			return b.WriteUint32(uint32(x))
+		}
+		return b.WriteUint64(x)
+	}
+	return fmt.Errorf("Can not pack %s as %q", v.Type(), wire)
+}
+
+func (b *Buffer) unpackValue(v reflect.Value, tag packTag) error {
+	order, prefix := b.order, b.prefix
+	defer func() { b.order, b.prefix = order, prefix }()
+	if tag.order != nil {
+		b.order = tag.order
+	}
+	if tag.hasPrefix {
+		b.prefix = tag.prefix
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return b.unpackStruct(v)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := b.unpackValue(v.Index(i), tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		n, err := b.readLength()
+		if err != nil {
+			return err
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && tag.wire == "" {
+			v.SetBytes(b.NextCopy(n))
+			return nil
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			if err := b.unpackValue(v.Index(i), tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	wire := tag.wire
+	if wire == "" {
+		wire = defaultWire(v.Kind())
+	}
+	switch wire {
+	case "lstring", "cstring", "pascal":
+		if v.Kind() != reflect.String {
+			break
+		}
+		var s string
+		var err error
+		switch wire {
+		case "cstring":
+			s, err = b.ReadCString()
+		case "pascal":
+			s, err = b.ReadPascalString()
+		default:
+			s, err = b.ReadLString()
+		}
+		v.SetString(s)
+		return err
+	case "bool":
+		if v.Kind() != reflect.Bool {
+			break
+		}
+		x, err := b.ReadBool()
+		v.SetBool(x)
+		return err
+	case "float32", "float64":
+		if !v.CanFloat() {
+			break
+		}
+		var x float64
+		var err error
+		if wire == "float32" {
+			var f float32
+			f, err = b.ReadFloat32()
+			x = float64(f)
+		} else {
+			x, err = b.ReadFloat64()
+		}
+		v.SetFloat(x)
+		return err
+	case "int8", "uint8", "int16", "uint16", "int32", "uint32", "int64", "uint64":
+		if !v.CanInt() && !v.CanUint() {
+			break
+		}
+		var x int64
+		var u uint64
+		var err error
+		switch wire {
+		case "int8":
+			var n int8
+			n, err = b.ReadInt8()
+			x = int64(n)
+		case "uint8":
+			var n uint8
+			n, err = b.ReadUint8()
+			u = uint64(n)
+		case "int16":
+			var n int16
+			n, err = b.ReadInt16()
+			x = int64(n)
+		case "uint16":
+			var n uint16
+			n, err = b.ReadUint16()
+			u = uint64(n)
+		case "int32":
+			var n int32
+			n, err = b.ReadInt32()
+			x = int64(n)
+		case "uint32":
+			var n uint32
+			n, err = b.ReadUint32()
+			u = uint64(n)
+		case "int64":
+			x, err = b.ReadInt64()
+		case "uint64":
+			u, err = b.ReadUint64()
+		}
+		if err != nil {
+			return err
+		}
+		if wire[0] == 'u' {
+			x = int64(u)
+		} else {
+			u = uint64(x)
+		}
+		if v.CanInt() {
+			if v.OverflowInt(x) || (wire[0] == 'u' && x < 0) {
+				return fmt.Errorf("Value overflows %s", v.Type())
+			}
+			v.SetInt(x)
+		} else {
+			if v.OverflowUint(u) || (wire[0] != 'u' && x < 0) {
+				return fmt.Errorf("Value overflows %s", v.Type())
+			}
+			v.SetUint(u)
+		}
+		return nil
+	}
+	return fmt.Errorf("Can not unpack %s as %q", v.Type(), wire)
+}
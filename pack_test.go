@@ -0,0 +1,133 @@
+package altdata
+
+import "encoding/binary"
+import "reflect"
+import "testing"
+
+type packInner struct {
+	A uint16 `altdata:"be"`
+}
+
+type packRecord struct {
+	Magic   uint32 `altdata:"be"`
+	Len     uint32
+	_       struct{} `altdata:"skip:3"`
+	Count   int      `altdata:"uint16,be"`
+	Name    string   `altdata:"prefix:uint8"`
+	C       string   `altdata:"cstring"`
+	P       string   `altdata:"pascal"`
+	Data    []byte
+	List    []int32 `altdata:"prefix:varint,be"`
+	Arr     [2]uint8
+	In      packInner
+	F       float64 `altdata:"float32"`
+	Ok      bool
+	Ignored string `altdata:"-"`
+	hidden  int
+}
+
+func TestPackMixedOrder(t *testing.T) {
+	b := NewBuffer(128)
+	in := packRecord{Magic: 0x01020304, Len: 5, Count: 300, Name: "hi", C: "c", P: "p", Data: []byte{9, 8},
+		List: []int32{-1, 2}, Arr: [2]uint8{3, 4}, In: packInner{7}, F: 1.5, Ok: true, Ignored: "x", hidden: 1}
+	if err := b.Pack(&in); err != nil {
+		t.Fatal(err)
+	}
+	if b.order != binary.LittleEndian {
+		t.Fatal("Pack should restore the byte order")
+	}
+	b.Flip()
+	raw := b.Bytes()
+	// Big endian magic, little endian Len, 3 bytes of skip, big endian Count.
+	if string(raw[:13]) != "\x01\x02\x03\x04\x05\x00\x00\x00\x00\x00\x00\x01\x2c" {
+		t.Fatalf("% x", raw[:13])
+	}
+	if string(raw[13:16]) != "\x02hi" {
+		t.Fatalf("% x", raw[13:16])
+	}
+	var out packRecord
+	if err := b.Unpack(&out); err != nil {
+		t.Fatal(err)
+	}
+	in.Ignored, in.hidden = "", 0
+	if !reflect.DeepEqual(in, out) || b.Remaining() != 0 {
+		t.Fatalf("%+v", out)
+	}
+}
+
+func TestPackRollback(t *testing.T) {
+	in := packRecord{Name: "hi", Data: []byte{1, 2, 3}}
+	w := NewBuffer(20)
+	w.WriteByte(0xaa)
+	if err := w.Pack(in); err == nil || w.Position() != 1 {
+		t.Fatal("failed Pack should not move", err, w.Position())
+	}
+	b := NewBuffer(128)
+	b.Pack(in)
+	b.Flip()
+	length := b.Remaining()
+	for n := 0; n < length; n++ {
+		b.SetManual(0, n)
+		var out packRecord
+		if err := b.Unpack(&out); err == nil || b.Position() != 0 {
+			t.Fatal(n, err)
+		}
+	}
+}
+
+func TestPackInvalid(t *testing.T) {
+	w := NewBuffer(16)
+	if err := w.Pack(1); err == nil {
+		t.Fatal("Pack of a non struct should fail")
+	}
+	var v packInner
+	if err := w.Unpack(v); err == nil {
+		t.Fatal("Unpack of a non pointer should fail")
+	}
+	var noWire struct{ N int }
+	if err := w.Pack(noWire); err == nil {
+		t.Fatal("int without wire type should fail")
+	}
+	var badTag struct {
+		N uint8 `altdata:"uint8,bogus"`
+	}
+	if err := w.Pack(badTag); err == nil {
+		t.Fatal("unknown tag option should fail")
+	}
+	var badSkip struct {
+		_ struct{} `altdata:"skip:-1"`
+	}
+	if err := w.Pack(badSkip); err == nil {
+		t.Fatal("negative skip should fail")
+	}
+	if w.Position() != 0 {
+		t.Fatal(w.Position())
+	}
+}
+
+func TestUnpackOverflow(t *testing.T) {
+	b := NewBuffer(8)
+	b.WriteUint16(300)
+	b.WriteInt8(-1)
+	b.Flip()
+	var small struct {
+		N int8 `altdata:"uint16"`
+	}
+	if err := b.Unpack(&small); err == nil || b.Position() != 0 {
+		t.Fatal("300 should overflow an int8", err)
+	}
+	var neg struct {
+		A uint16
+		N uint8 `altdata:"int8"`
+	}
+	if err := b.Unpack(&neg); err == nil || b.Position() != 0 {
+		t.Fatal("-1 should overflow a uint8", err)
+	}
+	var fits struct {
+		N int32 `altdata:"uint16"`
+		M int   `altdata:"int8"`
+	}
+	if err := b.Unpack(&fits); err != nil || fits.N != 300 || fits.M != -1 {
+		t.Fatal(fits, err)
+	}
+}
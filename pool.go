@@ -0,0 +1,54 @@
+package altdata
+
+import "encoding/binary"
+import "sync"
+
+// BufferPool reuses Buffers to reduce allocations and GC pressure.
+// It is safe for concurrent use.
+type BufferPool struct {
+	pool        sync.Pool
+	maxCapacity int
+	wipe        bool
+}
+
+// Creates a new pool. Buffers with a capacity larger than maxCapacity
+// are not retained by Put.
+func NewBufferPool(maxCapacity int) *BufferPool {
+	return &BufferPool{maxCapacity: maxCapacity}
+}
+
+// Enable or disable wiping buffers with Wipe when they are returned by Put.
+// Default is false. It is not synchronized, so call it before the pool is
+// shared between goroutines.
+func (p *BufferPool) SetWipe(wipe bool) {
+	p.wipe = wipe
+}
+
+// Returns a cleared buffer with at least given capacity, reusing a pooled
+// backing array if possible. The buffer is in the same state as from NewBuffer.
+func (p *BufferPool) Get(capacity int) *Buffer {
+	if buffer, ok := p.pool.Get().(*Buffer); ok {
+		if cap(buffer.data) >= capacity {
+			*buffer = Buffer{data: buffer.data, order: binary.LittleEndian}
+			buffer.Clear()
+			return buffer
+		}
+		p.pool.Put(buffer)
+	}
+	return NewBuffer(capacity)
+}
+
+// Returns the buffer to the pool. The buffer MUST NOT be used after Put,
+// as it may be handed out again by Get.
+func (p *BufferPool) Put(buffer *Buffer) {
+	if buffer == nil {
+		return
+	}
+	if p.wipe {
+		buffer.Wipe()
+	}
+	if cap(buffer.data) > p.maxCapacity {
+		return
+	}
+	p.pool.Put(buffer)
+}
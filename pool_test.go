@@ -0,0 +1,59 @@
+package altdata
+
+import "encoding/binary"
+import "math/big"
+import "testing"
+
+// Puts dirty buffers until one is handed out again, as sync.Pool may drop them.
+func reusedBuffer(t *testing.T, p *BufferPool, dirty func() *Buffer) (put, got *Buffer) {
+	for i := 0; i < 100; i++ {
+		put = dirty()
+		p.Put(put)
+		if got = p.Get(8); got == put {
+			return
+		}
+	}
+	t.Skip("pool never reused a buffer")
+	return
+}
+
+func TestPoolGetReset(t *testing.T) {
+	p := NewBufferPool(64)
+	_, b := reusedBuffer(t, p, func() *Buffer {
+		b := NewBufferWithOrder(16, binary.BigEndian)
+		b.SetGrowable(true)
+		b.SetStrict(true)
+		b.SetLengthPrefix(PrefixVarint)
+		b.WriteBigInt(big.NewInt(-1))
+		b.Flip()
+		b.Mark()
+		b.ReadUint8()
+		b.SetAppendMode(true)
+		return b
+	})
+	if b.Position() != 0 || b.Limit() != 16 || b.Mode() != ModeWrite || b.mark != -1 {
+		t.Fatal(b.Position(), b.Limit(), b.Mode(), b.mark)
+	}
+	if b.Order() != binary.LittleEndian || b.growable || b.append || b.strict || b.prefix != PrefixUint16 {
+		t.Fatal("pooled buffer kept its settings")
+	}
+	b.WriteUint16(1)
+	if b.data[0] != 1 {
+		t.Fatalf("% x", b.data[:2])
+	}
+}
+
+func TestPoolPutTooLarge(t *testing.T) {
+	p := NewBufferPool(32)
+	large := NewBuffer(64)
+	for i := 0; i < 10; i++ {
+		p.Put(large)
+		if b := p.Get(0); b == large {
+			t.Fatal("buffer above maxCapacity was pooled")
+		}
+	}
+	p.Put(nil)
+	if b := p.Get(4); b == nil || b.Capacity() < 4 {
+		t.Fatal(b)
+	}
+}
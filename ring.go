@@ -0,0 +1,92 @@
+package altdata
+
+import "io"
+
+// RingBuffer is a fixed size circular buffer for streaming reuse.
+// Writes wrap around at the end of the backing array and reads follow,
+// so it never reallocates. Unlike Buffer there is no Flip, reading and
+// writing use separate cursors.
+type RingBuffer struct {
+	data      []byte
+	read      int
+	write     int
+	length    int
+	overwrite bool
+}
+
+// Creates a new ring buffer with given capacity.
+// By default writes never overwrite unread data.
+func NewRingBuffer(capacity int) *RingBuffer {
+	return &RingBuffer{data: make([]byte, capacity)}
+}
+
+// Overwrite policy when a write does not fit in the free space.
+// When enabled the oldest unread bytes are overwritten and the read cursor
+// moves forward. When disabled (default) the write is truncated and returns
+// io.ErrShortWrite.
+func (r *RingBuffer) SetOverwrite(overwrite bool) {
+	r.overwrite = overwrite
+}
+
+func (r *RingBuffer) Capacity() int {
+	return len(r.data)
+}
+
+// Returns the number of unread bytes.
+func (r *RingBuffer) Length() int {
+	return r.length
+}
+
+// Returns the number of bytes that can be written without overwriting.
+func (r *RingBuffer) Free() int {
+	return len(r.data) - r.length
+}
+
+// Discards all unread bytes.
+func (r *RingBuffer) Clear() {
+	r.read = 0
+	r.write = 0
+	r.length = 0
+}
+
+// Implementing io.Reader. Returns io.EOF when no unread bytes remain.
+func (r *RingBuffer) Read(p []byte) (n int, err error) {
+	if r.length == 0 {
+		return 0, io.EOF
+	}
+	for n < len(p) && r.length > 0 {
+		end := r.read + r.length
+		if end > len(r.data) {
+			end = len(r.data)
+		}
+		c := copy(p[n:], r.data[r.read:end])
+		r.read = (r.read + c) % len(r.data)
+		r.length -= c
+		n += c
+	}
+	return
+}
+
+// Implementing io.Writer. See SetOverwrite for writes larger than the free space.
+func (r *RingBuffer) Write(p []byte) (n int, err error) {
+	if free := len(r.data) - r.length; len(p) > free && !r.overwrite {
+		p = p[:free]
+		err = io.ErrShortWrite
+	}
+	n = len(p)
+	if len(p) > len(r.data) {
+		p = p[len(p)-len(r.data):]
+	}
+	for len(p) > 0 {
+		c := copy(r.data[r.write:], p)
+		r.write = (r.write + c) % len(r.data)
+		r.length += c
+		p = p[c:]
+	}
+	if r.length > len(r.data) {
+		// The oldest unread bytes were overwritten.
+		r.length = len(r.data)
+		r.read = r.write
+	}
+	return
+}
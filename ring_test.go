@@ -0,0 +1,89 @@
+package altdata
+
+import "io"
+import "testing"
+
+func TestRingWrapAround(t *testing.T) {
+	r := NewRingBuffer(4)
+	if n, err := r.Write([]byte("abc")); n != 3 || err != nil {
+		t.Fatal(n, err)
+	}
+	p := make([]byte, 2)
+	if n, err := r.Read(p); n != 2 || err != nil || string(p) != "ab" {
+		t.Fatal(n, err, string(p))
+	}
+	// Writes past the end of the backing array and wraps to the start.
+	if n, err := r.Write([]byte("def")); n != 3 || err != nil {
+		t.Fatal(n, err)
+	}
+	if r.Length() != 4 || r.Free() != 0 {
+		t.Fatal(r.Length(), r.Free())
+	}
+	p = make([]byte, 8)
+	if n, err := r.Read(p); n != 4 || err != nil || string(p[:n]) != "cdef" {
+		t.Fatal(n, err, string(p[:n]))
+	}
+	if n, err := r.Read(p); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+}
+
+func TestRingShortWrite(t *testing.T) {
+	r := NewRingBuffer(4)
+	r.Write([]byte("ab"))
+	if n, err := r.Write([]byte("cdef")); n != 2 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+	if n, err := r.Write([]byte("g")); n != 0 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+	p := make([]byte, 4)
+	if n, _ := r.Read(p); string(p[:n]) != "abcd" {
+		t.Fatal(string(p[:n]))
+	}
+}
+
+func TestRingOverwrite(t *testing.T) {
+	r := NewRingBuffer(4)
+	r.SetOverwrite(true)
+	r.Write([]byte("abc"))
+	if n, err := r.Write([]byte("de")); n != 2 || err != nil {
+		t.Fatal(n, err)
+	}
+	p := make([]byte, 4)
+	if n, _ := r.Read(p); string(p[:n]) != "bcde" {
+		t.Fatal(string(p[:n]))
+	}
+}
+
+func TestRingWriteLargerThanCapacity(t *testing.T) {
+	r := NewRingBuffer(4)
+	if n, err := r.Write([]byte("abcdef")); n != 4 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+	r.Clear()
+	r.SetOverwrite(true)
+	r.Write([]byte("x"))
+	// Only the last bytes that fit are kept.
+	if n, err := r.Write([]byte("abcdef")); n != 6 || err != nil {
+		t.Fatal(n, err)
+	}
+	p := make([]byte, 8)
+	if n, _ := r.Read(p); string(p[:n]) != "cdef" {
+		t.Fatal(string(p[:n]))
+	}
+}
+
+func TestRingZeroCapacity(t *testing.T) {
+	r := NewRingBuffer(0)
+	if n, err := r.Write([]byte("a")); n != 0 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+	r.SetOverwrite(true)
+	if n, err := r.Write([]byte("a")); n != 1 || err != nil || r.Length() != 0 {
+		t.Fatal(n, err, r.Length())
+	}
+	if n, err := r.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+}
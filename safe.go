@@ -0,0 +1,15 @@
+package altdata
+
+import "fmt"
+
+// Runs fn and returns its error, or an error wrapping ErrPanic if fn panics,
+// like the Buffer methods that panic on invalid arguments do.
+// Only use it around code that leaves no broken state behind when it panics.
+func Safe(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanic, r)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,56 @@
+package altdata
+
+import "errors"
+import "strings"
+import "testing"
+
+func TestSafeWrapsPanic(t *testing.T) {
+	b := NewBuffer(4)
+	for name, f := range map[string]func() error{
+		"Next":           func() error { b.Next(-1); return nil },
+		"SetManual":      func() error { b.SetManual(3, 100); return nil },
+		"ChangePosition": func() error { b.ChangePosition(-1); return nil },
+		"Reset":          func() error { b.Reset(); return nil },
+	} {
+		err := Safe(f)
+		if !errors.Is(err, ErrPanic) {
+			t.Fatal(name, err)
+		}
+		if !strings.HasPrefix(err.Error(), ErrPanic.Error()+": ") {
+			t.Fatal(name, "message should include the panic", err)
+		}
+	}
+	if b.Position() != 0 {
+		t.Fatal(b.Position())
+	}
+}
+
+func TestSafeStrictNext(t *testing.T) {
+	b := NewBuffer(4)
+	b.SetStrict(true)
+	b.position = 5
+	err := Safe(func() error {
+		b.Next(1)
+		return nil
+	})
+	if !errors.Is(err, ErrPanic) || !strings.Contains(err.Error(), "Buffer.Next") {
+		t.Fatal(err)
+	}
+}
+
+func TestSafePassesErrors(t *testing.T) {
+	mine := errors.New("x")
+	if err := Safe(func() error { return mine }); err != mine {
+		t.Fatal(err)
+	}
+	if err := Safe(func() error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	err := Safe(func() error {
+		_, err := Wrap(nil).ReadUint32()
+		return err
+	})
+	if !errors.Is(err, ErrBufferEmpty) || errors.Is(err, ErrPanic) {
+		t.Fatal(err)
+	}
+}
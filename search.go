@@ -0,0 +1,60 @@
+package altdata
+
+import "bytes"
+
+// Returns the offset relative to the position of the first occurrence of pattern
+// in the remaining bytes, or -1 if not found. An empty pattern returns 0.
+// DOES NOT move the position.
+func (b *Buffer) IndexOf(pattern []byte) int {
+	return bytes.Index(b.Bytes(), pattern)
+}
+
+// Returns the offset relative to the position of the first c in the remaining
+// bytes, or -1 if not found. DOES NOT move the position.
+func (b *Buffer) IndexOfByte(c byte) int {
+	return bytes.IndexByte(b.Bytes(), c)
+}
+
+// Reports whether pattern occurs in the remaining bytes. DOES NOT move the position.
+func (b *Buffer) Contains(pattern []byte) bool {
+	return bytes.Contains(b.Bytes(), pattern)
+}
+
+// Returns the number of non-overlapping occurrences of pattern in the remaining bytes.
+// An empty pattern returns Remaining()+1. DOES NOT move the position.
+func (b *Buffer) Count(pattern []byte) int {
+	if len(pattern) == 0 {
+		return b.limit - b.position + 1
+	}
+	return bytes.Count(b.Bytes(), pattern)
+}
+
+// Returns the offset relative to the position of the last occurrence of pattern
+// in the remaining bytes, or -1 if not found. An empty pattern returns Remaining().
+// DOES NOT move the position.
+func (b *Buffer) LastIndexOf(pattern []byte) int {
+	return bytes.LastIndex(b.Bytes(), pattern)
+}
+
+// Moves the position past leading remaining bytes found in cutset,
+// like bytes.TrimLeft. Default cutset is a single zero byte.
+func (b *Buffer) TrimLeft(cutset ...byte) {
+	if len(cutset) == 0 {
+		cutset = []byte{0}
+	}
+	for b.position < b.limit && bytes.IndexByte(cutset, b.data[b.position]) >= 0 {
+		b.position++
+	}
+}
+
+// Moves the limit back past trailing remaining bytes found in cutset,
+// like bytes.TrimRight. Default cutset is a single zero byte.
+func (b *Buffer) TrimRight(cutset ...byte) {
+	if len(cutset) == 0 {
+		cutset = []byte{0}
+	}
+	for b.limit > b.position && bytes.IndexByte(cutset, b.data[b.limit-1]) >= 0 {
+		b.limit--
+	}
+	b.checkMark()
+}
@@ -0,0 +1,139 @@
+package altdata
+
+import "testing"
+
+func TestIndexOf(t *testing.T) {
+	b := Wrap([]byte("xabcabc"))
+	b.Skip(1)
+	if i := b.IndexOf([]byte("bc")); i != 1 {
+		t.Fatal(i)
+	}
+	if i := b.IndexOf([]byte("x")); i != -1 {
+		t.Fatal(i)
+	}
+	if i := b.IndexOf(nil); i != 0 {
+		t.Fatal("empty pattern should return 0, got", i)
+	}
+	if i := b.IndexOfByte('c'); i != 2 || b.Position() != 1 {
+		t.Fatal(i, b.Position())
+	}
+}
+
+func TestIndexOfEdges(t *testing.T) {
+	b := Wrap([]byte("aaXaa"))
+	b.SetManual(1, 4)
+	if i := b.IndexOf([]byte("a")); i != 0 {
+		t.Fatal(i)
+	}
+	if i := b.IndexOf([]byte("aa")); i != -1 {
+		t.Fatal("match beyond the limit", i)
+	}
+	if i := b.IndexOf([]byte("aXaa")); i != -1 {
+		t.Fatal("pattern longer than the remaining bytes", i)
+	}
+	if i := b.IndexOfByte('a'); i != 0 {
+		t.Fatal(i)
+	}
+	if i := b.IndexOfByte('b'); i != -1 {
+		t.Fatal(i)
+	}
+	e := Wrap(nil)
+	if e.IndexOf(nil) != 0 || e.IndexOf([]byte("a")) != -1 || e.LastIndexOf(nil) != 0 {
+		t.Fatal("empty buffer")
+	}
+}
+
+func TestContains(t *testing.T) {
+	b := Wrap([]byte("header:body"))
+	b.Skip(7)
+	if !b.Contains([]byte("od")) || !b.Contains([]byte("y")) || !b.Contains(nil) {
+		t.Fatal("Contains should find remaining bytes")
+	}
+	if b.Contains([]byte("header")) || b.Contains([]byte("body!")) {
+		t.Fatal("Contains should only search remaining bytes")
+	}
+}
+
+func TestCount(t *testing.T) {
+	b := Wrap([]byte("aaaaa\xffé"))
+	if c := b.Count([]byte("aa")); c != 2 {
+		t.Fatal("count should be non-overlapping, got", c)
+	}
+	if c := b.Count([]byte("a")); c != 5 {
+		t.Fatal(c)
+	}
+	if c := b.Count([]byte("b")); c != 0 {
+		t.Fatal(c)
+	}
+	// Counted in bytes, not runes like bytes.Count.
+	if c := b.Count(nil); c != b.Remaining()+1 {
+		t.Fatal(c)
+	}
+	b.Skip(2)
+	if c := b.Count([]byte("aa")); c != 1 || b.Position() != 2 {
+		t.Fatal(c)
+	}
+}
+
+func TestLastIndexOf(t *testing.T) {
+	b := Wrap([]byte("abcabcab"))
+	b.SetManual(1, 7)
+	if i := b.LastIndexOf([]byte("bc")); i != 3 {
+		t.Fatal(i)
+	}
+	if i := b.LastIndexOf([]byte("ab")); i != 2 {
+		t.Fatal("match beyond the limit", i)
+	}
+	if i := b.LastIndexOf([]byte("c")); i != 4 {
+		t.Fatal(i)
+	}
+	if i := b.LastIndexOf([]byte("x")); i != -1 {
+		t.Fatal(i)
+	}
+	if i := b.LastIndexOf(nil); i != b.Remaining() || b.Position() != 1 {
+		t.Fatal(i)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	b := Wrap([]byte{0, 0, 1, 2, 0, 3, 0})
+	b.TrimLeft()
+	b.TrimRight()
+	if string(b.Bytes()) != "\x01\x02\x00\x03" || b.Position() != 2 || b.Limit() != 6 {
+		t.Fatal(b.Bytes())
+	}
+	b.TrimLeft(1, 2, 0)
+	if string(b.Bytes()) != "\x03" {
+		t.Fatal(b.Bytes())
+	}
+}
+
+func TestTrimAllZero(t *testing.T) {
+	z := Wrap(make([]byte, 5))
+	z.TrimRight()
+	z.TrimLeft()
+	if z.Remaining() != 0 || z.Position() != 0 || z.Limit() != 0 {
+		t.Fatal(z.Position(), z.Limit())
+	}
+	l := Wrap(make([]byte, 5))
+	l.TrimLeft()
+	if l.Position() != 5 || l.Limit() != 5 {
+		t.Fatal(l.Position(), l.Limit())
+	}
+	e := Wrap(nil)
+	e.TrimLeft()
+	e.TrimRight()
+	if e.Position() != 0 || e.Limit() != 0 {
+		t.Fatal("empty buffer")
+	}
+}
+
+func TestTrimNoMatch(t *testing.T) {
+	n := Wrap([]byte{5, 6})
+	n.TrimLeft()
+	n.TrimRight(7)
+	n.TrimLeft(4, 6)
+	if n.Position() != 0 || n.Limit() != 2 {
+		t.Fatal(n.Position(), n.Limit())
+	}
+}
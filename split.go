@@ -0,0 +1,67 @@
+package altdata
+
+import "errors"
+import "fmt"
+
+// Splits the remaining bytes into records each preceded by a length of
+// prefixWidth bytes, 1, 2, 4 or 8, using the byte order. The records are slices
+// SHARING the backing array, see Slice. The position is moved to the limit.
+// Returns an error and does not move if the last record is truncated.
+func (b *Buffer) SplitLengthPrefixed(prefixWidth int) ([]*Buffer, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return nil, err
+	}
+	if prefixWidth != 1 && prefixWidth != 2 && prefixWidth != 4 && prefixWidth != 8 {
+		return nil, errors.New("Prefix width must be 1, 2, 4 or 8")
+	}
+	var records []*Buffer
+	position := b.position
+	for position < b.limit {
+		if b.limit-position < prefixWidth {
+			return nil, fmt.Errorf("Record truncated by end of buffer: %w", ErrBufferEmpty)
+		}
+		p := b.data[position : position+prefixWidth]
+		var n uint64
+		switch prefixWidth {
+		case 1:
+			n = uint64(p[0])
+		case 2:
+			n = uint64(b.order.Uint16(p))
+		case 4:
+			n = uint64(b.order.Uint32(p))
+		default:
+			n = b.order.Uint64(p)
+		}
+		position += prefixWidth
+		if n > uint64(b.limit-position) {
+			return nil, fmt.Errorf("Record truncated by end of buffer: %w", ErrBufferEmpty)
+		}
+		records = append(records, b.Slice(position, position+int(n)))
+		position += int(n)
+	}
+	b.tee(b.data[b.position:b.limit])
+	b.position = b.limit
+	return records, nil
+}
+
+// Splits the remaining bytes into records of recordSize bytes. The records are
+// slices SHARING the backing array, see Slice. The position is moved to the limit.
+// Returns an error and does not move if the last record is truncated.
+func (b *Buffer) SplitFixed(recordSize int) ([]*Buffer, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return nil, err
+	}
+	if recordSize <= 0 {
+		return nil, errors.New("Record size must be positive")
+	}
+	if (b.limit-b.position)%recordSize != 0 {
+		return nil, fmt.Errorf("Record truncated by end of buffer: %w", ErrBufferEmpty)
+	}
+	records := make([]*Buffer, 0, (b.limit-b.position)/recordSize)
+	for position := b.position; position < b.limit; position += recordSize {
+		records = append(records, b.Slice(position, position+recordSize))
+	}
+	b.tee(b.data[b.position:b.limit])
+	b.position = b.limit
+	return records, nil
+}
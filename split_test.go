@@ -0,0 +1,95 @@
+package altdata
+
+import "errors"
+import "testing"
+
+func lengthPrefixedRecords(order int) *Buffer {
+	b := NewBufferWithOrder(32, orders[order])
+	b.WriteUint16(2)
+	b.WriteString("ab")
+	b.WriteUint16(0)
+	b.WriteUint16(3)
+	b.WriteString("cde")
+	b.Flip()
+	return b
+}
+
+func TestSplitLengthPrefixedBoundary(t *testing.T) {
+	for i := range orders {
+		b := lengthPrefixedRecords(i)
+		r, err := b.SplitLengthPrefixed(2)
+		if err != nil || len(r) != 3 || b.Remaining() != 0 {
+			t.Fatal(len(r), err)
+		}
+		if string(r[0].Bytes()) != "ab" || r[1].Remaining() != 0 || string(r[2].Bytes()) != "cde" {
+			t.Fatal(r)
+		}
+		// Records share the backing array.
+		r[0].data[r[0].Position()] = 'z'
+		if b.data[2] != 'z' {
+			t.Fatal("records should share the backing array")
+		}
+	}
+}
+
+func TestSplitLengthPrefixedMidRecord(t *testing.T) {
+	b := lengthPrefixedRecords(0)
+	length := b.Limit()
+	// Cut short in the last body, and in the last prefix.
+	for _, limit := range []int{length - 1, 9, 8, 7} {
+		b.SetManual(0, limit)
+		if _, err := b.SplitLengthPrefixed(2); !errors.Is(err, ErrBufferEmpty) || b.Position() != 0 {
+			t.Fatal(limit, err)
+		}
+	}
+	// Ending right after a record is fine.
+	b.SetManual(0, 6)
+	if r, err := b.SplitLengthPrefixed(2); err != nil || len(r) != 2 {
+		t.Fatal(len(r), err)
+	}
+}
+
+func TestSplitLengthPrefixedWidths(t *testing.T) {
+	for _, width := range []int{1, 4, 8} {
+		b := NewBuffer(32)
+		switch width {
+		case 1:
+			b.WriteUint8(1)
+		case 4:
+			b.WriteUint32(1)
+		case 8:
+			b.WriteUint64(1)
+		}
+		b.WriteString("x")
+		b.Flip()
+		if r, err := b.SplitLengthPrefixed(width); err != nil || len(r) != 1 || string(r[0].Bytes()) != "x" {
+			t.Fatal(width, err)
+		}
+	}
+	huge := Wrap([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 'x'})
+	if _, err := huge.SplitLengthPrefixed(8); err == nil || huge.Position() != 0 {
+		t.Fatal(err)
+	}
+	if _, err := huge.SplitLengthPrefixed(3); err == nil {
+		t.Fatal("width 3 should fail")
+	}
+	if r, err := Wrap(nil).SplitLengthPrefixed(2); err != nil || len(r) != 0 {
+		t.Fatal(r, err)
+	}
+}
+
+func TestSplitFixed(t *testing.T) {
+	f := Wrap([]byte("abcdef"))
+	if r, err := f.SplitFixed(3); err != nil || len(r) != 2 || string(r[1].Bytes()) != "def" || f.Remaining() != 0 {
+		t.Fatal(r, err)
+	}
+	f.Rewind()
+	if _, err := f.SplitFixed(4); !errors.Is(err, ErrBufferEmpty) || f.Position() != 0 {
+		t.Fatal(err)
+	}
+	for _, size := range []int{0, -1} {
+		if _, err := f.SplitFixed(size); err == nil {
+			t.Fatal(size)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package altdata
+
+import "fmt"
+import "runtime"
+import "strings"
+
+// Enable or disable strict mode. In strict mode the invariant
+// 0 <= position <= limit <= capacity is checked before operations that access
+// the data, panicking with a message naming the method instead of an opaque
+// slice bounds error. Read and write methods also return an error when called
+// in the wrong Mode. Default is false.
+func (b *Buffer) SetStrict(strict bool) {
+	b.strict = strict
+}
+
+// Will panic if position or limit are out of range!
+func (b *Buffer) check() {
+	if 0 <= b.position && b.position <= b.limit && b.limit <= cap(b.data) {
+		return
+	}
+	panic(fmt.Sprintf("Buffer in invalid state in %s: position %d, limit %d, capacity %d!",
+		callerMethod(), b.position, b.limit, cap(b.data)))
+}
+
+// Returns the name of the first exported Buffer method on the call stack.
+func callerMethod() string {
+	pc := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pc[:runtime.Callers(3, pc)])
+	for {
+		frame, more := frames.Next()
+		name := frame.Function[strings.LastIndex(frame.Function, ".")+1:]
+		if strings.Contains(frame.Function, "(*Buffer).") && name != "" && name[0] >= 'A' && name[0] <= 'Z' {
+			return "Buffer." + name
+		}
+		if !more {
+			return "Buffer"
+		}
+	}
+}
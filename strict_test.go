@@ -0,0 +1,42 @@
+package altdata
+
+import "fmt"
+import "strings"
+import "testing"
+
+func TestStrictCorruptedState(t *testing.T) {
+	b := NewBuffer(4)
+	b.SetStrict(true)
+	b.position = -1
+	for name, f := range map[string]func(){
+		"Buffer.ReadUint32":  func() { b.ReadUint32() },
+		"Buffer.WriteUint16": func() { b.WriteUint16(1) },
+		"Buffer.Bytes":       func() { b.Bytes() },
+	} {
+		msg := fmt.Sprint(catchPanic(f))
+		if !strings.Contains(msg, name) || !strings.Contains(msg, "position -1") {
+			t.Fatal(name, msg)
+		}
+	}
+	b.position, b.limit = 2, 8
+	msg := fmt.Sprint(catchPanic(func() { b.Compact() }))
+	if !strings.Contains(msg, "Buffer.Compact") || !strings.Contains(msg, "limit 8, capacity 4") {
+		t.Fatal(msg)
+	}
+}
+
+func TestStrictValidState(t *testing.T) {
+	b := NewBuffer(4)
+	b.SetStrict(true)
+	b.WriteUint32(1)
+	b.Flip()
+	if v, err := b.ReadUint32(); err != nil || v != 1 {
+		t.Fatal(v, err)
+	}
+	// Without strict mode the same state fails with a runtime error.
+	b.SetStrict(false)
+	b.position = -1
+	if msg := fmt.Sprint(catchPanic(func() { b.Bytes() })); strings.Contains(msg, "Buffer.Bytes") {
+		t.Fatal(msg)
+	}
+}
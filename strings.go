@@ -0,0 +1,281 @@
+package altdata
+
+import "bytes"
+import "errors"
+import "fmt"
+import "io"
+import "math"
+import "strings"
+import "unicode/utf16"
+import "unicode/utf8"
+
+// Encoding of the length prefix written before strings by WriteLString.
+type LengthPrefix int
+
+const (
+	PrefixUint16 LengthPrefix = iota // 2 byte length using the byte order. Default.
+	PrefixUint8                      // 1 byte length.
+	PrefixUint32                     // 4 byte length using the byte order.
+	PrefixVarint                     // 7-bit encoded length, like .NET BinaryWriter and protobuf.
+)
+
+// Set the length prefix used by WriteLString and ReadLString. Default is PrefixUint16.
+func (b *Buffer) SetLengthPrefix(prefix LengthPrefix) {
+	b.prefix = prefix
+}
+
+// Writes n using the length prefix encoding.
+func (b *Buffer) writeLength(n int) error {
+	switch b.prefix {
+	case PrefixUint8:
+		if n > math.MaxUint8 {
+			return errors.New("Length too large for uint8 prefix")
+		}
+		return b.WriteUint8(uint8(n))
+	case PrefixUint32:
+		if uint64(n) > math.MaxUint32 {
+			return errors.New("Length too large for uint32 prefix")
+		}
+		return b.WriteUint32(uint32(n))
+	case PrefixVarint:
+		_, err := b.WriteUvarint(uint64(n))
+		return err
+	default:
+		if n > math.MaxUint16 {
+			return errors.New("Length too large for uint16 prefix")
+		}
+		return b.WriteUint16(uint16(n))
+	}
+}
+
+// Reads a length using the length prefix encoding.
+// Returns an error if the length is larger than the remaining bytes.
+func (b *Buffer) readLength() (int, error) {
+	var n uint64
+	switch b.prefix {
+	case PrefixUint8:
+		v, err := b.ReadUint8()
+		if err != nil {
+			return 0, err
+		}
+		n = uint64(v)
+	case PrefixUint32:
+		v, err := b.ReadUint32()
+		if err != nil {
+			return 0, err
+		}
+		n = uint64(v)
+	case PrefixVarint:
+		v, _, err := b.ReadUvarint()
+		if err != nil {
+			return 0, err
+		}
+		n = v
+	default:
+		v, err := b.ReadUint16()
+		if err != nil {
+			return 0, err
+		}
+		n = uint64(v)
+	}
+	if n > uint64(b.limit-b.position) {
+		return 0, fmt.Errorf("Length exceeds remaining bytes in buffer: %w", ErrBufferEmpty)
+	}
+	return int(n), nil
+}
+
+// Writes a length prefixed string, see SetLengthPrefix.
+// Returns an error and does not move the position if the string does not fit.
+func (b *Buffer) WriteLString(s string) error {
+	start := b.writePosition()
+	if err := b.writeLength(len(s)); err != nil {
+		b.setWritePosition(start)
+		return err
+	}
+	p, err := b.reserve(len(s))
+	if err != nil {
+		b.setWritePosition(start)
+		return err
+	}
+	copy(p, s)
+	return nil
+}
+
+// Reads a length prefixed string, see SetLengthPrefix.
+// Returns an error and does not move the position if the declared length
+// exceeds the remaining bytes.
+func (b *Buffer) ReadLString() (string, error) {
+	start := b.position
+	n, err := b.readLength()
+	if err != nil {
+		b.position = start
+		return "", err
+	}
+	return string(b.Next(n)), nil
+}
+
+// Reads a NUL terminated string and moves the position past the terminator.
+// Returns an error and does not move if there is no terminator before the limit.
+func (b *Buffer) ReadCString() (string, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return "", err
+	}
+	i := bytes.IndexByte(b.Bytes(), 0)
+	if i < 0 {
+		return "", errors.New("No NUL terminator in buffer")
+	}
+	str := string(b.data[b.position : b.position+i])
+	b.position += i + 1
+	b.tee(b.data[b.position-i-1 : b.position])
+	return str, nil
+}
+
+// Writes s followed by a NUL terminator.
+// Returns an error if s contains a NUL or does not fit.
+func (b *Buffer) WriteCString(s string) error {
+	if strings.IndexByte(s, 0) >= 0 {
+		return errors.New("String contains NUL")
+	}
+	p, err := b.reserve(len(s) + 1)
+	if err != nil {
+		return err
+	}
+	p[copy(p, s)] = 0
+	return nil
+}
+
+// Reads n UTF-16 code units using the byte order and decodes them to a string.
+// Returns an error and does not move if fewer than 2*n bytes remain.
+func (b *Buffer) ReadUTF16(n int) (string, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return "", err
+	}
+	if n < 0 {
+		return "", errors.New("Tryed to read negative number of code units from Buffer")
+	}
+	if n > (b.limit-b.position)/2 {
+		return "", ErrBufferEmpty
+	}
+	p := b.Next(2 * n)
+	units := make([]uint16, n)
+	for i := range units {
+		units[i] = b.order.Uint16(p[2*i:])
+	}
+	return string(utf16.Decode(units)), nil
+}
+
+// Writes s encoded as UTF-16 using the byte order.
+// Returns the number of code units written, or an error if s does not fit.
+func (b *Buffer) WriteUTF16(s string) (int, error) {
+	units := utf16.Encode([]rune(s))
+	p, err := b.reserve(2 * len(units))
+	if err != nil {
+		return 0, err
+	}
+	for i, u := range units {
+		b.order.PutUint16(p[2*i:], u)
+	}
+	return len(units), nil
+}
+
+// Reads a UTF-16 string prefixed with its number of code units, see SetLengthPrefix.
+// Returns an error and does not move the position if it is truncated.
+func (b *Buffer) ReadLUTF16() (string, error) {
+	start := b.position
+	n, err := b.readLength()
+	if err == nil {
+		var str string
+		if str, err = b.ReadUTF16(n); err == nil {
+			return str, nil
+		}
+	}
+	b.position = start
+	return "", err
+}
+
+// Writes s as UTF-16 prefixed with its number of code units, see SetLengthPrefix.
+// Returns an error and does not move the position if it does not fit.
+func (b *Buffer) WriteLUTF16(s string) error {
+	start := b.writePosition()
+	err := b.writeLength(len(utf16.Encode([]rune(s))))
+	if err == nil {
+		_, err = b.WriteUTF16(s)
+	}
+	if err != nil {
+		b.setWritePosition(start)
+	}
+	return err
+}
+
+// Reads a line terminated by "\n" or "\r\n" and returns it without the terminator.
+// An empty line returns "". A "\r" not followed by "\n" is part of the line.
+// If there is no "\n" before the limit the remaining bytes are returned with io.EOF.
+func (b *Buffer) ReadLine() (string, error) {
+	line, err := b.ReadUntilExclusive('\n')
+	if err == io.EOF {
+		return string(b.Next(b.Remaining())), io.EOF
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSuffix(line, []byte{'\r'})), nil
+}
+
+// Reads a Pascal string, a length byte followed by that many bytes.
+// Returns an error and does not move if the declared length exceeds the remaining bytes.
+func (b *Buffer) ReadPascalString() (string, error) {
+	n, err := b.PeekByte()
+	if err != nil {
+		return "", err
+	}
+	if int(n) > b.limit-b.position-1 {
+		return "", fmt.Errorf("Length exceeds remaining bytes in buffer: %w", ErrBufferEmpty)
+	}
+	b.position++
+	b.tee(b.data[b.position-1 : b.position])
+	return string(b.Next(int(n))), nil
+}
+
+// Writes a Pascal string, a length byte followed by the bytes of s.
+// Returns an error if s is longer than 255 bytes or does not fit.
+func (b *Buffer) WritePascalString(s string) error {
+	if len(s) > math.MaxUint8 {
+		return errors.New("String too long for Pascal string")
+	}
+	p, err := b.reserve(len(s) + 1)
+	if err != nil {
+		return err
+	}
+	p[0] = byte(len(s))
+	copy(p[1:], s)
+	return nil
+}
+
+// Implementing io.RuneReader. Decodes the next UTF-8 rune and moves the position past it.
+// Invalid UTF-8, including a rune cut off by the limit, returns utf8.RuneError
+// with size 1 like strings.Reader. Returns io.EOF if no bytes remain.
+func (b *Buffer) ReadRune() (r rune, size int, err error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, 0, err
+	}
+	if b.position >= b.limit {
+		return 0, 0, io.EOF
+	}
+	r, size = utf8.DecodeRune(b.data[b.position:b.limit])
+	b.position += size
+	b.tee(b.data[b.position-size : b.position])
+	b.unread = b.position
+	b.runeSize = size
+	return
+}
+
+// Implementing io.RuneScanner. Moves the position back to before the last rune.
+// Returns an error if the previous operation was not a successful ReadRune or ReadByte.
+func (b *Buffer) UnreadRune() error {
+	if b.unread == 0 || b.unread != b.position {
+		return errors.New("Previous operation was not ReadRune or ReadByte")
+	}
+	b.position -= b.runeSize
+	b.unread = 0
+	return nil
+}
@@ -0,0 +1,257 @@
+package altdata
+
+import "io"
+import "testing"
+import "unicode/utf8"
+
+func TestCString(t *testing.T) {
+	b := NewBuffer(16)
+	if err := b.WriteCString(""); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.WriteCString("abc"); err != nil {
+		t.Fatal(err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != "\x00abc\x00" {
+		t.Fatalf("%q", b.Bytes())
+	}
+	if s, err := b.ReadCString(); s != "" || err != nil {
+		t.Fatal(s, err)
+	}
+	if s, err := b.ReadCString(); s != "abc" || err != nil || b.Remaining() != 0 {
+		t.Fatal(s, err)
+	}
+}
+
+func TestCStringNoTerminator(t *testing.T) {
+	b := Wrap([]byte("abc"))
+	if _, err := b.ReadCString(); err == nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+}
+
+func TestCStringEmbeddedNUL(t *testing.T) {
+	b := NewBuffer(16)
+	if err := b.WriteCString("a\x00b"); err == nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+}
+
+func TestUTF16RoundTrip(t *testing.T) {
+	golden := map[string][]byte{"LittleEndian": {0x61, 0x00, 0x3d, 0xd8, 0x00, 0xde},
+		"BigEndian": {0x00, 0x61, 0xd8, 0x3d, 0xde, 0x00}}
+	for _, order := range orders {
+		b := NewBufferWithOrder(32, order)
+		// U+1F600 is encoded as the surrogate pair D83D DE00.
+		n, err := b.WriteUTF16("a\U0001F600")
+		if err != nil || n != 3 {
+			t.Fatal(n, err)
+		}
+		b.Flip()
+		if string(b.Bytes()) != string(golden[order.String()]) {
+			t.Fatalf("%s: % x", order, b.Bytes())
+		}
+		if s, err := b.ReadUTF16(3); s != "a\U0001F600" || err != nil {
+			t.Fatal(order, s, err)
+		}
+	}
+}
+
+func TestLUTF16RoundTrip(t *testing.T) {
+	for _, order := range orders {
+		b := NewBufferWithOrder(32, order)
+		if err := b.WriteLUTF16("héllo \U0001F600"); err != nil {
+			t.Fatal(err)
+		}
+		b.Flip()
+		if s, err := b.ReadLUTF16(); s != "héllo \U0001F600" || err != nil || b.Remaining() != 0 {
+			t.Fatal(order, s, err)
+		}
+	}
+}
+
+func TestUTF16Short(t *testing.T) {
+	b := Wrap([]byte{0x61, 0x00, 0x62})
+	if _, err := b.ReadUTF16(2); err == nil || b.Position() != 0 {
+		t.Fatal(err)
+	}
+	if _, err := b.ReadUTF16(-1); err == nil {
+		t.Fatal("negative count should fail")
+	}
+}
+
+func TestPascalString(t *testing.T) {
+	max := string(make([]byte, 255))
+	for _, s := range []string{"", "a", "hello", max} {
+		b := NewBuffer(256)
+		if err := b.WritePascalString(s); err != nil {
+			t.Fatal(len(s), err)
+		}
+		b.Flip()
+		if b.Length() != len(s)+1 || b.Bytes()[0] != byte(len(s)) {
+			t.Fatal(len(s), b.Length())
+		}
+		if r, err := b.ReadPascalString(); err != nil || r != s || b.Remaining() != 0 {
+			t.Fatal(len(r), err)
+		}
+	}
+}
+
+func TestPascalStringTooLong(t *testing.T) {
+	b := NewBuffer(512)
+	if err := b.WritePascalString(string(make([]byte, 256))); err == nil || b.Position() != 0 {
+		t.Fatal("256 byte Pascal string should fail", err)
+	}
+	if err := NewBuffer(3).WritePascalString("abc"); err == nil {
+		t.Fatal("WritePascalString should fail in 3 bytes")
+	}
+}
+
+func TestPascalStringTruncated(t *testing.T) {
+	for _, data := range []string{"", "\x04abc", "\xff"} {
+		b := Wrap([]byte(data))
+		if _, err := b.ReadPascalString(); err == nil || b.Position() != 0 {
+			t.Fatalf("%q: %v", data, err)
+		}
+	}
+}
+
+func TestReadRune(t *testing.T) {
+	b := Wrap([]byte("aæ€😀"))
+	for _, want := range []struct {
+		r    rune
+		size int
+	}{{'a', 1}, {'æ', 2}, {'€', 3}, {'😀', 4}} {
+		if r, size, err := b.ReadRune(); err != nil || r != want.r || size != want.size {
+			t.Fatal(r, size, err)
+		}
+	}
+	if _, _, err := b.ReadRune(); err != io.EOF {
+		t.Fatal(err)
+	}
+}
+
+func TestReadRuneCutByLimit(t *testing.T) {
+	data := []byte("a€b")
+	b := WrapRange(data, 0, 3)
+	b.Skip(1)
+	if r, size, err := b.ReadRune(); err != nil || r != utf8.RuneError || size != 1 || b.Position() != 2 {
+		t.Fatal(r, size, err)
+	}
+	if r, size, err := b.ReadRune(); err != nil || r != utf8.RuneError || size != 1 {
+		t.Fatal(r, size, err)
+	}
+	if _, _, err := b.ReadRune(); err != io.EOF {
+		t.Fatal(err)
+	}
+	i := Wrap([]byte{0xff, 'x'})
+	if r, size, _ := i.ReadRune(); r != utf8.RuneError || size != 1 {
+		t.Fatal("invalid UTF-8", r, size)
+	}
+}
+
+func TestUnreadRune(t *testing.T) {
+	b := Wrap([]byte("é€x"))
+	if b.UnreadRune() == nil {
+		t.Fatal("UnreadRune before any read should fail")
+	}
+	b.ReadRune()
+	b.ReadRune()
+	if err := b.UnreadRune(); err != nil || b.Position() != 2 {
+		t.Fatal(err, b.Position())
+	}
+	if b.UnreadRune() == nil {
+		t.Fatal("UnreadRune twice should fail")
+	}
+	if r, _, _ := b.ReadRune(); r != '€' {
+		t.Fatal(r)
+	}
+	b.ReadByte()
+	if err := b.UnreadRune(); err != nil || b.Position() != 5 {
+		t.Fatal("UnreadRune after ReadByte should move one byte", err, b.Position())
+	}
+	b.ReadRune()
+	b.Skip(0)
+	if b.UnreadRune() == nil {
+		t.Fatal("UnreadRune after Skip should fail")
+	}
+	b.SetManual(0, 6)
+	b.ReadRune()
+	b.ReadUint8()
+	if b.UnreadRune() == nil {
+		t.Fatal("UnreadRune after ReadUint8 should fail")
+	}
+	var _ io.RuneScanner = b
+}
+
+func FuzzTryNextString(f *testing.F) {
+	f.Add([]byte("abc"), 0, 2)
+	f.Add([]byte("abc"), 1, -1)
+	f.Add([]byte{}, 0, 1<<31)
+	f.Fuzz(func(t *testing.T, data []byte, skip, n int) {
+		b := Wrap(data)
+		if skip >= 0 && skip <= len(data) {
+			b.Skip(skip)
+		}
+		start := b.Position()
+		s, err := b.TryNextString(n)
+		if n < 0 || n > len(data)-start {
+			if err == nil || b.Position() != start {
+				t.Fatal(n, err, b.Position())
+			}
+			return
+		}
+		if err != nil || s != string(data[start:start+n]) || b.Position() != start+n {
+			t.Fatal(n, err)
+		}
+	})
+}
+
+func FuzzReadLString(f *testing.F) {
+	f.Add([]byte("\x03\x00abc"), uint8(PrefixUint16))
+	f.Add([]byte("\xff\xff\xff\xffabc"), uint8(PrefixUint32))
+	f.Add([]byte("\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01"), uint8(PrefixVarint))
+	f.Add([]byte("\x05ab"), uint8(PrefixUint8))
+	f.Fuzz(func(t *testing.T, data []byte, prefix uint8) {
+		b := Wrap(data)
+		b.SetLengthPrefix(LengthPrefix(prefix % 4))
+		s, err := b.ReadLString()
+		if err != nil {
+			if b.Position() != 0 {
+				t.Fatal("failed ReadLString moved to", b.Position())
+			}
+			return
+		}
+		if b.Position() < len(s) || string(data[b.Position()-len(s):b.Position()]) != s {
+			t.Fatal(s, b.Position())
+		}
+	})
+}
+
+func TestReadLine(t *testing.T) {
+	type line struct {
+		s   string
+		err error
+	}
+	for _, c := range []struct {
+		in    string
+		lines []line
+	}{
+		{"a\r\nb", []line{{"a", nil}, {"b", io.EOF}}},
+		{"a\rb\n", []line{{"a\rb", nil}, {"", io.EOF}}},
+		{"\n", []line{{"", nil}, {"", io.EOF}}},
+		{"", []line{{"", io.EOF}}},
+		{"a\n\r\n", []line{{"a", nil}, {"", nil}, {"", io.EOF}}},
+	} {
+		b := Wrap([]byte(c.in))
+		for i, l := range c.lines {
+			if s, err := b.ReadLine(); s != l.s || err != l.err {
+				t.Fatalf("%q line %d: %q %v", c.in, i, s, err)
+			}
+		}
+		if b.Remaining() != 0 {
+			t.Fatalf("%q: %d remaining", c.in, b.Remaining())
+		}
+	}
+}
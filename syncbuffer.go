@@ -0,0 +1,128 @@
+package altdata
+
+import "io"
+import "sync"
+
+// SyncBuffer wraps a Buffer with a mutex so it can be shared between goroutines.
+// Methods returning bytes return copies, so the internal storage is never
+// exposed without the lock held. Settings of the wrapped Buffer, like
+// SetGrowable or SetByteOrder, must be changed inside Do as well.
+type SyncBuffer struct {
+	mu     sync.Mutex
+	buffer *Buffer
+}
+
+// Creates a new SyncBuffer with given capacity. Default byte order is LittleEndian.
+func NewSyncBuffer(capacity int) *SyncBuffer {
+	return &SyncBuffer{buffer: NewBuffer(capacity)}
+}
+
+// Runs fn with the lock held, for operations without a SyncBuffer method.
+// The Buffer and slices obtained from it MUST NOT be retained after fn returns.
+func (s *SyncBuffer) Do(fn func(b *Buffer)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.buffer)
+}
+
+func (s *SyncBuffer) Capacity() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.Capacity()
+}
+
+func (s *SyncBuffer) Length() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.Length()
+}
+
+// Implementing io.Reader.
+func (s *SyncBuffer) Read(p []byte) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.Read(p)
+}
+
+func (s *SyncBuffer) ReadByte() (byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.ReadByte()
+}
+
+func (s *SyncBuffer) ReadFrom(reader io.Reader) (n int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.ReadFrom(reader)
+}
+
+// Implementing io.Writer.
+func (s *SyncBuffer) Write(p []byte) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.Write(p)
+}
+
+func (s *SyncBuffer) WriteByte(in byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.WriteByte(in)
+}
+
+func (s *SyncBuffer) WriteString(str string) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.WriteString(str)
+}
+
+func (s *SyncBuffer) WriteTo(writer io.Writer) (n int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.WriteTo(writer)
+}
+
+func (s *SyncBuffer) Flip() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffer.Flip()
+}
+
+func (s *SyncBuffer) Rewind() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffer.Rewind()
+}
+
+func (s *SyncBuffer) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.buffer.Clear()
+}
+
+// Returns a copy of the remaining bytes. DOES NOT move the position.
+func (s *SyncBuffer) Bytes() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]byte(nil), s.buffer.Bytes()...)
+}
+
+// Returns a copy of up to n bytes. Panic if n is negative!
+func (s *SyncBuffer) Next(n int) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]byte(nil), s.buffer.Next(n)...)
+}
+
+// Wrapper for encoding/binary.Read.
+func (s *SyncBuffer) ReadBinary(data interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.ReadBinary(data)
+}
+
+// Wrapper for encoding/binary.Write.
+func (s *SyncBuffer) WriteBinary(data interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buffer.WriteBinary(data)
+}
@@ -0,0 +1,46 @@
+package altdata
+
+import "sync"
+import "testing"
+
+// Run with -race to check the locking.
+func TestSyncBufferConcurrent(t *testing.T) {
+	s := NewSyncBuffer(8)
+	s.Do(func(b *Buffer) { b.SetAppendMode(true) })
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Write([]byte{1, 2})
+				s.WriteByte(3)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.ReadByte()
+				s.Length()
+				s.Bytes()
+			}
+		}()
+	}
+	wg.Wait()
+	total := 0
+	s.Do(func(b *Buffer) { total = b.Position() + b.Remaining() })
+	if total != 4*100*3 {
+		t.Fatal(total)
+	}
+}
+
+func TestSyncBufferCopies(t *testing.T) {
+	s := NewSyncBuffer(4)
+	s.Write([]byte("ab"))
+	s.Flip()
+	p := s.Bytes()
+	p[0] = 'z'
+	if n := s.Next(1); string(n) != "a" {
+		t.Fatal(string(n))
+	}
+}
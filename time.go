@@ -0,0 +1,49 @@
+package altdata
+
+import "errors"
+import "math"
+import "time"
+
+// Range of times that fit in int64 Unix nanoseconds.
+var minNanoTime, maxNanoTime = time.Unix(0, math.MinInt64), time.Unix(0, math.MaxInt64)
+
+// Writes t as an int64 of Unix nanoseconds using the byte order.
+// Only times between the years 1678 and 2262 can be represented, others,
+// like the zero time, return an error and are not written. Use WriteTimeFull for those.
+func (b *Buffer) WriteTime(t time.Time) error {
+	if t.Before(minNanoTime) || t.After(maxNanoTime) {
+		return errors.New("Time out of range for Unix nanoseconds")
+	}
+	return b.WriteInt64(t.UnixNano())
+}
+
+// Reads a time written by WriteTime. The returned time is in UTC.
+func (b *Buffer) ReadTime() (time.Time, error) {
+	v, err := b.ReadInt64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, v).UTC(), nil
+}
+
+// Writes t as an int64 of Unix seconds followed by a uint32 of nanoseconds,
+// using the byte order. Any time, including the zero time, can be represented.
+// Returns an error and does not move if there is not space for 12 bytes.
+func (b *Buffer) WriteTimeFull(t time.Time) error {
+	p, err := b.reserve(12)
+	if err != nil {
+		return err
+	}
+	b.order.PutUint64(p, uint64(t.Unix()))
+	b.order.PutUint32(p[8:], uint32(t.Nanosecond()))
+	return nil
+}
+
+// Reads a time written by WriteTimeFull. The returned time is in UTC.
+func (b *Buffer) ReadTimeFull() (time.Time, error) {
+	p, err := b.read(12)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(b.order.Uint64(p)), int64(b.order.Uint32(p[8:]))).UTC(), nil
+}
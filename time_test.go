@@ -0,0 +1,56 @@
+package altdata
+
+import "testing"
+import "time"
+
+func TestTimeRoundTrip(t *testing.T) {
+	times := []time.Time{time.Unix(0, 0), time.Date(2024, 2, 29, 12, 30, 1, 123456789, time.UTC),
+		time.Date(1700, 1, 1, 0, 0, 0, 1, time.UTC), time.Date(2262, 1, 1, 0, 0, 0, 0, time.UTC),
+		minNanoTime, maxNanoTime}
+	for _, order := range orders {
+		for _, v := range times {
+			b := NewBufferWithOrder(8, order)
+			if err := b.WriteTime(v); err != nil {
+				t.Fatal(v, err)
+			}
+			b.Flip()
+			if r, err := b.ReadTime(); err != nil || !r.Equal(v) || r.Location() != time.UTC {
+				t.Fatal(v, r, err)
+			}
+		}
+	}
+}
+
+func TestTimeOutOfRange(t *testing.T) {
+	b := NewBuffer(8)
+	for _, v := range []time.Time{{}, minNanoTime.Add(-1), maxNanoTime.Add(1),
+		time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)} {
+		if err := b.WriteTime(v); err == nil || b.Position() != 0 {
+			t.Fatal(v, err)
+		}
+	}
+}
+
+func TestTimeFullRoundTrip(t *testing.T) {
+	times := []time.Time{{}, time.Unix(0, 0), time.Date(1, 1, 1, 0, 0, 0, 1, time.UTC),
+		time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(-500, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 12, 0, 0, 5, time.FixedZone("X", 3600))}
+	for _, order := range orders {
+		for _, v := range times {
+			b := NewBufferWithOrder(12, order)
+			if err := b.WriteTimeFull(v); err != nil {
+				t.Fatal(v, err)
+			}
+			b.Flip()
+			r, err := b.ReadTimeFull()
+			if err != nil || !r.Equal(v) {
+				t.Fatal(v, r, err)
+			}
+		}
+	}
+	var zero Buffer
+	zero.UnmarshalBinary(make([]byte, 11))
+	if _, err := zero.ReadTimeFull(); err == nil {
+		t.Fatal("ReadTimeFull of 11 bytes should fail")
+	}
+}
@@ -0,0 +1,49 @@
+package altdata
+
+import "encoding/hex"
+import "errors"
+
+// Writes the 16 bytes of u as they are. UUIDs are big endian by RFC 4122,
+// so the byte order of the Buffer is NOT used.
+func (b *Buffer) WriteUUID(u [16]byte) error {
+	p, err := b.reserve(16)
+	if err != nil {
+		return err
+	}
+	copy(p, u[:])
+	return nil
+}
+
+// Reads the 16 bytes of a UUID as they are, the byte order is NOT used.
+// Returns an error and does not move if fewer than 16 bytes remain.
+func (b *Buffer) ReadUUID() ([16]byte, error) {
+	var u [16]byte
+	p, err := b.read(16)
+	if err != nil {
+		return u, err
+	}
+	copy(u[:], p)
+	return u, nil
+}
+
+// Writes a UUID given in the canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func (b *Buffer) WriteUUIDString(s string) error {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return errors.New("Invalid UUID string")
+	}
+	var u [16]byte
+	if _, err := hex.Decode(u[:], []byte(s[0:8]+s[9:13]+s[14:18]+s[19:23]+s[24:])); err != nil {
+		return errors.New("Invalid UUID string")
+	}
+	return b.WriteUUID(u)
+}
+
+// Reads a UUID and returns it in the canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
+func (b *Buffer) ReadUUIDString() (string, error) {
+	u, err := b.ReadUUID()
+	if err != nil {
+		return "", err
+	}
+	h := hex.EncodeToString(u[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
@@ -0,0 +1,58 @@
+package altdata
+
+import "testing"
+
+func TestUUIDByteOrder(t *testing.T) {
+	u := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	for _, order := range orders {
+		b := NewBufferWithOrder(16, order)
+		if err := b.WriteUUID(u); err != nil {
+			t.Fatal(err)
+		}
+		b.Flip()
+		if string(b.Bytes()) != string(u[:]) {
+			t.Fatalf("%v: % x", order, b.Bytes())
+		}
+		if r, err := b.ReadUUID(); err != nil || r != u {
+			t.Fatal(order, r, err)
+		}
+	}
+}
+
+func TestUUIDShort(t *testing.T) {
+	b := NewBuffer(15)
+	if err := b.WriteUUID([16]byte{}); err == nil || b.Position() != 0 {
+		t.Fatal("WriteUUID should fail in 15 bytes", err)
+	}
+	b = Wrap(make([]byte, 15))
+	if _, err := b.ReadUUID(); err == nil || b.Position() != 0 {
+		t.Fatal("ReadUUID should fail with 15 bytes", err)
+	}
+}
+
+func TestUUIDString(t *testing.T) {
+	s := "123e4567-e89b-12d3-a456-426614174000"
+	b := NewBuffer(16)
+	if err := b.WriteUUIDString("123E4567-E89B-12D3-A456-426614174000"); err != nil {
+		t.Fatal(err)
+	}
+	b.Flip()
+	if b.Bytes()[0] != 0x12 || b.Bytes()[15] != 0x00 {
+		t.Fatalf("% x", b.Bytes())
+	}
+	if r, err := b.ReadUUIDString(); err != nil || r != s {
+		t.Fatal(r, err)
+	}
+}
+
+func TestUUIDStringInvalid(t *testing.T) {
+	b := NewBuffer(16)
+	for _, s := range []string{"", "123e4567e89b12d3a456426614174000",
+		"123e4567-e89b-12d3-a456-42661417400", "123e4567-e89b-12d3-a456-4266141740000",
+		"123e4567_e89b-12d3-a456-426614174000", "123e4567-e89b-12d3-a456-42661417400g",
+		"{23e4567-e89b-12d3-a456-426614174000"} {
+		if err := b.WriteUUIDString(s); err == nil || b.Position() != 0 {
+			t.Fatalf("%q: %v", s, err)
+		}
+	}
+}
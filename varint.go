@@ -0,0 +1,98 @@
+package altdata
+
+import "bytes"
+import "encoding/binary"
+import "errors"
+import "fmt"
+
+// Writes x as an unsigned base 128 varint. The byte order is not used.
+// Returns the number of bytes written, or an error if there is not space enough.
+func (b *Buffer) WriteUvarint(x uint64) (int, error) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	p, err := b.reserve(n)
+	if err != nil {
+		return 0, err
+	}
+	return copy(p, tmp[:n]), nil
+}
+
+// Reads an unsigned base 128 varint. The byte order is not used.
+// Returns the value and the number of bytes read. The position is not moved
+// if the varint is truncated by the limit or overflows 64 bits.
+func (b *Buffer) ReadUvarint() (uint64, int, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return 0, 0, err
+	}
+	x, n := binary.Uvarint(b.data[b.position:b.limit])
+	if n == 0 {
+		return 0, 0, fmt.Errorf("Varint truncated by end of buffer: %w", ErrBufferEmpty)
+	}
+	if n < 0 {
+		return 0, 0, errors.New("Varint overflows 64 bits")
+	}
+	b.position += n
+	b.unread = 0
+	b.tee(b.data[b.position-n : b.position])
+	return x, n, nil
+}
+
+// Writes x as a zig-zag encoded signed varint, so small negative values stay short.
+// Returns the number of bytes written, or an error if there is not space enough.
+func (b *Buffer) WriteVarint(x int64) (int, error) {
+	ux := uint64(x) << 1
+	if x < 0 {
+		ux = ^ux
+	}
+	return b.WriteUvarint(ux)
+}
+
+// Reads a zig-zag encoded signed varint.
+// Returns the value and the number of bytes read, see ReadUvarint for errors.
+func (b *Buffer) ReadVarint() (int64, int, error) {
+	ux, n, err := b.ReadUvarint()
+	x := int64(ux >> 1)
+	if ux&1 != 0 {
+		x = ^x
+	}
+	return x, n, err
+}
+
+// Writes the length of p as an unsigned varint followed by p.
+// Returns an error and writes nothing if there is not space enough.
+func (b *Buffer) WriteVarBytes(p []byte) error {
+	start := b.writePosition()
+	if _, err := b.WriteUvarint(uint64(len(p))); err != nil {
+		return err
+	}
+	data, err := b.reserve(len(p))
+	if err != nil {
+		b.setWritePosition(start)
+		return err
+	}
+	copy(data, p)
+	return nil
+}
+
+// Reads an unsigned varint length followed by that many bytes, returned as a copy.
+// Returns an error and does not move if the length is larger than the remaining bytes.
+func (b *Buffer) ReadVarBytes() ([]byte, error) {
+	if err := b.checkMode(ModeRead); err != nil {
+		return nil, err
+	}
+	x, n := binary.Uvarint(b.data[b.position:b.limit])
+	if n == 0 {
+		return nil, fmt.Errorf("Varint truncated by end of buffer: %w", ErrBufferEmpty)
+	}
+	if n < 0 {
+		return nil, errors.New("Varint overflows 64 bits")
+	}
+	if x > uint64(b.limit-b.position-n) {
+		return nil, fmt.Errorf("Length exceeds remaining bytes in buffer: %w", ErrBufferEmpty)
+	}
+	data, err := b.read(n + int(x))
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(data[n:]), nil
+}
@@ -0,0 +1,138 @@
+package altdata
+
+import "encoding/binary"
+import "math"
+import "testing"
+
+func TestUvarintBoundaries(t *testing.T) {
+	cases := []struct {
+		v    uint64
+		size int
+	}{{0, 1}, {127, 1}, {128, 2}, {16383, 2}, {16384, 3}, {math.MaxUint64, 10}}
+	for _, c := range cases {
+		b := NewBuffer(16)
+		n, err := b.WriteUvarint(c.v)
+		if err != nil || n != c.size || b.Position() != c.size {
+			t.Fatal(c.v, n, err)
+		}
+		b.Flip()
+		v, n, err := b.ReadUvarint()
+		if err != nil || v != c.v || n != c.size || b.Remaining() != 0 {
+			t.Fatal(c.v, v, n, err)
+		}
+	}
+}
+
+func TestUvarintTruncated(t *testing.T) {
+	b := Wrap([]byte{0x80, 0x80})
+	if _, _, err := b.ReadUvarint(); err == nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+	empty := Wrap(nil)
+	if _, _, err := empty.ReadUvarint(); err == nil {
+		t.Fatal("ReadUvarint of empty buffer should fail")
+	}
+}
+
+func TestUvarintOverflow(t *testing.T) {
+	b := Wrap([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+	if _, _, err := b.ReadUvarint(); err == nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+}
+
+func TestUvarintNoSpace(t *testing.T) {
+	b := NewBuffer(1)
+	if _, err := b.WriteUvarint(128); err == nil || b.Position() != 0 {
+		t.Fatal(err, b.Position())
+	}
+}
+
+func TestVarintSizes(t *testing.T) {
+	cases := []struct {
+		v    int64
+		size int
+	}{{0, 1}, {-1, 1}, {63, 1}, {-64, 1}, {64, 2}, {math.MaxInt64, binary.MaxVarintLen64},
+		{math.MinInt64, binary.MaxVarintLen64}}
+	for _, c := range cases {
+		b := NewBuffer(16)
+		if n, err := b.WriteVarint(c.v); err != nil || n != c.size {
+			t.Fatal(c.v, n, err)
+		}
+		b.Flip()
+		if v, n, err := b.ReadVarint(); err != nil || v != c.v || n != c.size {
+			t.Fatal(c.v, v, n, err)
+		}
+	}
+}
+
+func FuzzVarint(f *testing.F) {
+	for _, v := range []int64{0, -1, 1, math.MinInt64, math.MaxInt64} {
+		f.Add(v)
+	}
+	f.Fuzz(func(t *testing.T, v int64) {
+		b := NewBuffer(binary.MaxVarintLen64)
+		if _, err := b.WriteVarint(v); err != nil {
+			t.Fatal(err)
+		}
+		b.Flip()
+		if want := binary.AppendVarint(nil, v); string(b.Bytes()) != string(want) {
+			t.Fatalf("%d encoded as % x, encoding/binary gives % x", v, b.Bytes(), want)
+		}
+		if r, _, err := b.ReadVarint(); err != nil || r != v {
+			t.Fatal(v, r, err)
+		}
+	})
+}
+
+func TestVarBytes(t *testing.T) {
+	b := NewBuffer(16)
+	b.WriteVarBytes([]byte("abc"))
+	b.WriteVarBytes(nil)
+	b.Flip()
+	if p, err := b.ReadVarBytes(); err != nil || string(p) != "abc" {
+		t.Fatal(p, err)
+	}
+	if p, err := b.ReadVarBytes(); err != nil || len(p) != 0 || b.Remaining() != 0 {
+		t.Fatal(p, err)
+	}
+	s := NewBuffer(3)
+	if err := s.WriteVarBytes([]byte("abc")); err == nil || s.Position() != 0 {
+		t.Fatal(err)
+	}
+}
+
+func TestVarBytesStrictMode(t *testing.T) {
+	b := NewBuffer(8)
+	b.SetStrict(true)
+	b.WriteVarBytes([]byte("abc"))
+	b.SetManual(0, 4)
+	if _, err := b.ReadVarBytes(); err == nil || b.Position() != 0 {
+		t.Fatal("ReadVarBytes in write mode should fail", err)
+	}
+	b.SetMode(ModeRead)
+	if p, err := b.ReadVarBytes(); err != nil || string(p) != "abc" {
+		t.Fatal(p, err)
+	}
+}
+
+func FuzzReadVarBytes(f *testing.F) {
+	f.Add([]byte("\x03abc"))
+	f.Add([]byte("\x80\x01"))
+	f.Add([]byte("\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01"))
+	f.Add([]byte("\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		b := Wrap(data)
+		p, err := b.ReadVarBytes()
+		if err != nil {
+			if b.Position() != 0 {
+				t.Fatal("failed ReadVarBytes moved to", b.Position())
+			}
+			return
+		}
+		x, n := binary.Uvarint(data)
+		if uint64(len(p)) != x || b.Position() != n+len(p) || string(data[n:b.Position()]) != string(p) {
+			t.Fatal(len(p), b.Position())
+		}
+	})
+}
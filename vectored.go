@@ -0,0 +1,39 @@
+package altdata
+
+import "io"
+
+// Writes each slice in order, growing or truncating like Write.
+// Returns total bytes written, stopping with io.ErrShortWrite at the first
+// slice that did not fit.
+func (b *Buffer) WriteBuffers(bufs ...[]byte) (int, error) {
+	total := 0
+	for _, p := range bufs {
+		n, err := b.Write(p)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Fills each destination in order from the remaining bytes.
+// Returns total bytes read, io.EOF if nothing was left to read, or
+// io.ErrUnexpectedEOF if the buffer ran out part way.
+func (b *Buffer) ReadInto(dsts ...[]byte) (int, error) {
+	total := 0
+	for _, p := range dsts {
+		n, err := b.Read(p)
+		total += n
+		if err != nil && err != io.EOF {
+			return total, err
+		}
+		if n < len(p) {
+			if total == 0 {
+				return 0, io.EOF
+			}
+			return total, io.ErrUnexpectedEOF
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,54 @@
+package altdata
+
+import "io"
+import "testing"
+
+func TestWriteBuffers(t *testing.T) {
+	b := NewBuffer(6)
+	n, err := b.WriteBuffers([]byte("ab"), nil, []byte("cde"), []byte("fgh"), []byte("ij"))
+	if n != 6 || err != io.ErrShortWrite {
+		t.Fatal(n, err)
+	}
+	b.Flip()
+	if string(b.Bytes()) != "abcdef" {
+		t.Fatal(string(b.Bytes()))
+	}
+	g := NewGrowableBuffer(1)
+	if n, err := g.WriteBuffers([]byte("abc"), []byte("defg")); n != 7 || err != nil {
+		t.Fatal(n, err)
+	}
+	if n, err := g.WriteBuffers(); n != 0 || err != nil {
+		t.Fatal(n, err)
+	}
+}
+
+func TestReadIntoSpanning(t *testing.T) {
+	b := Wrap([]byte("abcdefgh"))
+	x, y, z := make([]byte, 1), make([]byte, 3), make([]byte, 2)
+	if n, err := b.ReadInto(x, y, z); n != 6 || err != nil || string(x)+string(y)+string(z) != "abcdef" {
+		t.Fatal(n, err)
+	}
+	// The buffer runs out inside the second destination.
+	y, z = make([]byte, 1), make([]byte, 4)
+	n, err := b.ReadInto(y, z, x)
+	if n != 2 || err != io.ErrUnexpectedEOF || string(y) != "g" || string(z[:1]) != "h" || b.Remaining() != 0 {
+		t.Fatal(n, err, string(y), string(z))
+	}
+	if n, err := b.ReadInto(x); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+	if n, err := b.ReadInto(nil, []byte{}); n != 0 || err != nil {
+		t.Fatal("empty destinations should not fail", n, err)
+	}
+}
+
+func TestReadIntoEndsOnBoundary(t *testing.T) {
+	b := Wrap([]byte("abcd"))
+	x, y := make([]byte, 2), make([]byte, 2)
+	if n, err := b.ReadInto(x, y); n != 4 || err != nil {
+		t.Fatal(n, err)
+	}
+	if n, err := b.ReadInto(x, y); n != 0 || err != io.EOF {
+		t.Fatal(n, err)
+	}
+}
@@ -0,0 +1,22 @@
+package altdata
+
+import "runtime"
+
+// Overwrites the whole backing array with zeros and clears the buffer.
+// This is best effort, copies made by Resize, Clone or callers, and any the
+// Go runtime made while moving memory, are not wiped.
+func (b *Buffer) Wipe() {
+	b.WipeRange(0, cap(b.data))
+	b.Clear()
+}
+
+// Overwrites the absolute range [start:end] of the backing array with zeros.
+// DOES NOT move the position or limit. Best effort like Wipe.
+// Will panic if start > end or end > capacity!
+func (b *Buffer) WipeRange(start, end int) {
+	if start < 0 || start > end || end > cap(b.data) {
+		panic("Buffer range out of range!")
+	}
+	clear(b.data[start:end])
+	runtime.KeepAlive(b.data)
+}
@@ -0,0 +1,51 @@
+package altdata
+
+import "testing"
+
+func TestWipe(t *testing.T) {
+	b := NewBuffer(8)
+	b.WriteString("secret")
+	b.Flip()
+	data := b.data[:cap(b.data)]
+	b.Wipe()
+	for i, c := range data {
+		if c != 0 {
+			t.Fatal(i, c)
+		}
+	}
+	if b.Position() != 0 || b.Limit() != 8 {
+		t.Fatal(b.Position(), b.Limit())
+	}
+}
+
+func TestWipeRange(t *testing.T) {
+	b := Wrap([]byte("abcdef"))
+	b.Skip(1)
+	b.WipeRange(2, 4)
+	if string(b.data) != "ab\x00\x00ef" || b.Position() != 1 || b.Limit() != 6 {
+		t.Fatalf("%q %d %d", b.data, b.Position(), b.Limit())
+	}
+	for _, r := range [][2]int{{-1, 2}, {3, 2}, {0, 7}} {
+		if catchPanic(func() { b.WipeRange(r[0], r[1]) }) == nil {
+			t.Fatal("expected panic", r)
+		}
+	}
+	if string(b.data) != "ab\x00\x00ef" {
+		t.Fatalf("%q", b.data)
+	}
+}
+
+func TestPoolWipe(t *testing.T) {
+	p := NewBufferPool(64)
+	p.SetWipe(true)
+	_, got := reusedBuffer(t, p, func() *Buffer {
+		b := NewBuffer(16)
+		b.WriteString("password")
+		return b
+	})
+	for i, c := range got.data[:cap(got.data)] {
+		if c != 0 {
+			t.Fatal("old data visible after Get", i, c)
+		}
+	}
+}